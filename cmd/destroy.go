@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator"
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/eduardoths/micro-cli/generator/plugin"
+	"github.com/eduardoths/micro-cli/generator/regions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newDestroyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "destroy",
+		Aliases: []string{"d"},
+		Short:   "Remove the files created by a generator",
+		Long: "Remove the files created by a generator.\n\n" +
+			"destroy <name> <Entity> removes the files the manifest records as generated by the " + plugin.PREFIX + "<name>\n" +
+			"executable when <name> is not a destroy subcommand.",
+		Args: cobra.ArbitraryArgs,
+		RunE: destroyPlugin,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.String(DIR_FLAG, ".", "project root directory")
+	flags.String(MODULE_FLAG, "", "project module path (defaults to the module in go.mod)")
+	flags.String(STRUCTS_DIR_FLAG, DEFAULT_STRUCTS_DIR, "directory, relative to the module, holding entity structs")
+	flags.Bool(FORCE_FLAG, false, "remove files modified since generated")
+
+	for _, g := range generator.Generators() {
+		cmd.AddCommand(newDestroyEntityCommand(generatorDestroy(g)))
+	}
+	cmd.AddCommand(
+		newDestroyEntityCommand(destroyCommand{
+			use:   "entity <Entity>",
+			short: "Remove an entity struct with its repository, service, decorators and handler",
+			paths: func(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error) {
+				paths := []string{name.FilePath()}
+				for _, layerPaths := range []func(generateOptions, entity.EntityName, *pflag.FlagSet) ([]string, error){
+					repositoryPaths, servicePaths, handlerPaths,
+				} {
+					layer, err := layerPaths(opts, name, flags)
+					if err != nil {
+						return nil, err
+					}
+					paths = append(paths, layer...)
+				}
+				return paths, nil
+			},
+			syncOpenAPI: true,
+		}),
+	)
+	return cmd
+}
+
+// destroyCommand describes a destroy subcommand: paths computes the files a
+// generator creates for the entity, the same way the generator does. The
+// generators without paths, such as plugins, destroy the files the manifest
+// records as generated by them.
+type destroyCommand struct {
+	use         string
+	aliases     []string
+	short       string
+	generator   string
+	flags       func(flags *pflag.FlagSet)
+	paths       func(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error)
+	syncOpenAPI bool
+}
+
+// builtinDestroys describes the destroy subcommands of the builtin
+// generators, whose files are found even when the manifest misses them.
+var builtinDestroys = map[string]destroyCommand{
+	"repository": {
+		short: "Remove the repository of an entity and its tests",
+		paths: repositoryPaths,
+	},
+	"service": {
+		short: "Remove the service of an entity and its tests",
+		paths: servicePaths,
+	},
+	"handler": {
+		short: "Remove the HTTP handler of an entity and its tests",
+		paths: handlerPaths,
+	},
+	"proto": {
+		short: "Remove the protobuf service of an entity and its gRPC server adapter",
+		paths: func(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error) {
+			p, err := entity.NewProto(name, opts.module, file.Struct{}, entity.DefaultProtoTypes())
+			if err != nil {
+				return nil, err
+			}
+			return []string{p.ProtoFilePath(), p.FilePath()}, nil
+		},
+	},
+	"decorator": {
+		short: "Remove the decorators of the repository or service of an entity",
+		flags: func(flags *pflag.FlagSet) {
+			flags.StringArray(KIND_FLAG, nil, "kind of the decorators to remove, one of "+
+				strings.Join(entity.DecoratorKinds(), ", ")+", every kind when not given")
+			flags.StringArray(LAYER_FLAG, nil, "layer of the decorated interface, one of "+
+				strings.Join(entity.Layers(), ", ")+", every layer when not given")
+		},
+		paths: decoratorPaths,
+	},
+	"struct": {
+		short: "Remove a struct generated from sample JSON payloads",
+		paths: func(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error) {
+			return []string{name.FilePath()}, nil
+		},
+	},
+}
+
+// generatorDestroy describes the destroy subcommand of a registered
+// generator.
+func generatorDestroy(g generator.Generator) destroyCommand {
+	d, ok := builtinDestroys[g.Name()]
+	if !ok {
+		d.short = "Remove the files generated for an entity by the " + g.Name() + " generator"
+	}
+	d.use = g.Name() + " <Entity>"
+	d.generator = g.Name()
+	if describer, ok := g.(generator.Describer); ok {
+		d.aliases = describer.Aliases()
+	}
+	if routes, ok := g.(generator.RouteGenerator); ok {
+		d.syncOpenAPI = routes.GeneratesRoutes()
+	}
+	return d
+}
+
+// destroyPlugin removes the files generated for an entity by the generator
+// of an unknown destroy subcommand, run from PATH, as recorded in the
+// manifest.
+func destroyPlugin(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	if len(args) != 2 || args[1] == "" {
+		return fmt.Errorf("expected the entity name after %s", args[0])
+	}
+	return destroyEntity(cmd, destroyCommand{generator: args[0]}, args[1])
+}
+
+func repositoryPaths(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error) {
+	repo := entity.NewRepository(name, opts.module)
+	decorators, err := layerDecoratorPaths(opts, name, entity.REPOSITORY_LAYER, entity.DecoratorKinds())
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{repo.FilePath(), repo.TestFilePath()}, existingPaths(opts, decorators)...), nil
+}
+
+func servicePaths(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error) {
+	service := entity.NewService(name, opts.module)
+	decorators, err := layerDecoratorPaths(opts, name, entity.SERVICE_LAYER, entity.DecoratorKinds())
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{service.FilePath(), service.TestFilePath()}, existingPaths(opts, decorators)...), nil
+}
+
+func handlerPaths(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error) {
+	handler := entity.NewHandler(name, opts.module)
+	return []string{handler.FilePath(), handler.TestFilePath()}, nil
+}
+
+// decoratorPaths returns the decorators of the kinds and layers given as
+// flags, the existing ones of any kind or layer when not given.
+func decoratorPaths(opts generateOptions, name entity.EntityName, flags *pflag.FlagSet) ([]string, error) {
+	kinds, err := flags.GetStringArray(KIND_FLAG)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := flags.GetStringArray(LAYER_FLAG)
+	if err != nil {
+		return nil, err
+	}
+	all := len(kinds) == 0 || len(layers) == 0
+	if len(kinds) == 0 {
+		kinds = entity.DecoratorKinds()
+	}
+	if len(layers) == 0 {
+		layers = entity.Layers()
+	}
+
+	paths := make([]string, 0)
+	for _, layer := range layers {
+		layerPaths, err := layerDecoratorPaths(opts, name, layer, kinds)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, layerPaths...)
+	}
+	if all {
+		return existingPaths(opts, paths), nil
+	}
+	return paths, nil
+}
+
+// layerDecoratorPaths returns the paths of the decorators of the kinds of
+// the interface of the entity in the layer.
+func layerDecoratorPaths(opts generateOptions, name entity.EntityName, layer string, kinds []string) ([]string, error) {
+	iface, err := entity.LayerInterface(name, opts.module, layer)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		path, err := entity.DecoratorPath(iface, kind)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// existingPaths returns the paths of the files found in the project.
+func existingPaths(opts generateOptions, paths []string) []string {
+	existing := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(opts.dir, path)); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	return existing
+}
+
+func newDestroyEntityCommand(d destroyCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     d.use,
+		Aliases: d.aliases,
+		Short:   d.short,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return destroyEntity(cmd, d, args[0])
+		},
+	}
+	if d.flags != nil {
+		d.flags(cmd.Flags())
+	}
+	return cmd
+}
+
+// destroyEntity removes the files of the entity computed by the destroy
+// command or, when it does not compute them, the ones recorded in the
+// manifest as generated by its generator, failing when there are none.
+func destroyEntity(cmd *cobra.Command, d destroyCommand, arg string) error {
+	opts, err := readProjectOptions(cmd)
+	if err != nil {
+		return err
+	}
+	name := opts.entityName(arg)
+	var paths []string
+	if d.paths != nil {
+		paths, err = d.paths(opts, name, cmd.Flags())
+	} else {
+		paths, err = recordedPaths(opts, d.generator, name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no file generated by %s found for %s", d.generator, name.PascalCase())
+	}
+	if err := destroyFiles(cmd, opts, paths); err != nil {
+		return err
+	}
+	if d.syncOpenAPI {
+		return syncOpenAPI(cmd, opts)
+	}
+	return nil
+}
+
+// recordedPaths returns the paths of the manifest generated for the entity
+// by the generator, whether registered or run from PATH, the command line of
+// which reads generate <generator> <Entity>.
+func recordedPaths(opts generateOptions, generator string, name entity.EntityName) ([]string, error) {
+	m, err := manifest.Load(opts.dir)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0)
+	for _, entry := range m.Files {
+		command := append(strings.Fields(entry.Inputs.Generator), entry.Inputs.Args...)
+		if len(command) < 3 || command[0] != GENERATE_COMMAND || command[1] != generator {
+			continue
+		}
+		if opts.entityName(command[2]).PascalCase() == name.PascalCase() {
+			paths = append(paths, entry.Path)
+		}
+	}
+	return paths, nil
+}
+
+// destroyFiles removes the files and the directories they leave empty. No
+// file is removed when one of them was modified since generated, unless
+// forced.
+func destroyFiles(cmd *cobra.Command, opts generateOptions, paths []string) error {
+	m, err := manifest.Load(opts.dir)
+	if err != nil {
+		return err
+	}
+
+	existing := make([]string, 0, len(paths))
+	modified := make([]string, 0)
+	for _, path := range paths {
+		content, err := os.ReadFile(filepath.Join(opts.dir, path))
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(cmd.OutOrStdout(), "skipped %s, not found\n", filepath.Clean(path))
+			m.Remove(path)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		existing = append(existing, path)
+		if !generated(m, path, content) {
+			modified = append(modified, filepath.Clean(path))
+		}
+	}
+	if len(modified) > 0 && !opts.force {
+		return fmt.Errorf("%s modified since generated, use --%s to remove anyway",
+			strings.Join(modified, ", "), FORCE_FLAG)
+	}
+
+	for _, path := range existing {
+		if err := removeFile(opts.dir, path); err != nil {
+			return err
+		}
+		m.Remove(path)
+		fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", filepath.Clean(path))
+	}
+	return m.Save(opts.dir)
+}
+
+// generated reports whether content is the one generated for path, as
+// recorded in the manifest or, for files it misses, by their regions.
+func generated(m manifest.Manifest, path string, content []byte) bool {
+	if entry, ok := m.Find(path); ok {
+		return manifest.Hash(content) == entry.Hash
+	}
+	return regions.Pristine(content)
+}
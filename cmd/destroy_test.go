@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+const REPOSITORY_FILE = "src/repositories/xpto_struct/xpto_struct_repository.go"
+
+// generatedProject returns a project with the repository of XptoStruct
+// generated, along with an unrelated file of the repositories directory.
+func generatedProject(t *testing.T) string {
+	t.Helper()
+	dir := writeProject(t, map[string]string{
+		"go.mod": "module github.com/e/svc\n\ngo 1.19\n",
+		"src/structs/xpto_struct.go": "package structs\n\n" +
+			"type XptoStruct struct {\n" +
+			"\tName string `json:\"name\"`\n" +
+			"}\n",
+		"src/repositories/doc.go": "// Package repositories holds the repositories.\npackage repositories\n",
+	})
+	if out, err := run(t, "generate", "repository", "XptoStruct", "--dir", dir); err != nil {
+		t.Fatalf("generate failed: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func modify(t *testing.T, dir string, name string) {
+	t.Helper()
+	fullPath := filepath.Join(dir, filepath.FromSlash(name))
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fullPath, append(content, "\n// edited\n"...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	t.Run("it should refuse to remove files modified since generated", func(t *testing.T) {
+		dir := generatedProject(t)
+		modify(t, dir, REPOSITORY_FILE)
+
+		out, err := run(t, "destroy", "repository", "XptoStruct", "--dir", dir)
+		if err == nil || !strings.Contains(err.Error(), "modified since generated") {
+			t.Fatalf("expected a modified error, got %v\n%s", err, out)
+		}
+		if !exists(dir, REPOSITORY_FILE) {
+			t.Errorf("%s was removed", REPOSITORY_FILE)
+		}
+	})
+
+	t.Run("it should remove files modified since generated when forced", func(t *testing.T) {
+		dir := generatedProject(t)
+		modify(t, dir, REPOSITORY_FILE)
+
+		if out, err := run(t, "destroy", "repository", "XptoStruct", "--force", "--dir", dir); err != nil {
+			t.Fatalf("destroy failed: %v\n%s", err, out)
+		}
+		if exists(dir, REPOSITORY_FILE) {
+			t.Errorf("%s was not removed", REPOSITORY_FILE)
+		}
+	})
+
+	t.Run("it should prune the directories left empty", func(t *testing.T) {
+		dir := generatedProject(t)
+
+		if out, err := run(t, "destroy", "repository", "XptoStruct", "--dir", dir); err != nil {
+			t.Fatalf("destroy failed: %v\n%s", err, out)
+		}
+		if exists(dir, "src/repositories/xpto_struct") {
+			t.Errorf("src/repositories/xpto_struct was not pruned")
+		}
+		if !exists(dir, "src/repositories") {
+			t.Errorf("src/repositories was pruned while not empty")
+		}
+	})
+
+	t.Run("it should leave the files the manifest does not list untouched", func(t *testing.T) {
+		dir := generatedProject(t)
+		queries := "src/repositories/xpto_struct/queries.sql"
+		want := "SELECT name FROM xpto_structs;\n"
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(queries)), []byte(want), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if out, err := run(t, "destroy", "repository", "XptoStruct", "--dir", dir); err != nil {
+			t.Fatalf("destroy failed: %v\n%s", err, out)
+		}
+		for _, name := range []string{"src/structs/xpto_struct.go", "src/repositories/doc.go"} {
+			if !exists(dir, name) {
+				t.Errorf("%s was removed", name)
+			}
+		}
+		if got := readFile(t, dir, queries); got != want {
+			utils.Error(t, want, got)
+		}
+	})
+}
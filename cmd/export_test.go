@@ -0,0 +1,4 @@
+package cmd
+
+// NewRootCommand exposes the root command to the tests of the commands.
+var NewRootCommand = newRootCommand
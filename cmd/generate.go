@@ -0,0 +1,491 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator"
+	"github.com/eduardoths/micro-cli/generator/builtin"
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/eduardoths/micro-cli/generator/plugin"
+	"github.com/eduardoths/micro-cli/generator/verify"
+	"github.com/eduardoths/micro-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DIR_FLAG                = "dir"
+	MODULE_FLAG             = "module"
+	STRUCTS_DIR_FLAG        = "structs-dir"
+	FORCE_FLAG              = "force"
+	VALUE_RECEIVERS_FLAG    = "value-receivers"
+	NO_CONSTRUCTOR_FLAG     = "no-constructor"
+	NO_ASSERTION_FLAG       = "no-assertion"
+	FUNCTIONAL_OPTIONS_FLAG = "functional-options"
+	DEPENDENCY_FLAG         = "dependency"
+	TAG_FLAG                = "tag"
+	FRAMEWORK_FLAG          = builtin.FRAMEWORK_FLAG
+	TYPE_MAP_FLAG           = builtin.TYPE_MAP_FLAG
+	KIND_FLAG               = builtin.KIND_FLAG
+	LAYER_FLAG              = builtin.LAYER_FLAG
+	FROM_SQL_FLAG           = "from-sql"
+	NULLABLE_FLAG           = "nullable"
+	DIALECT_FLAG            = "dialect"
+
+	DEFAULT_STRUCTS_DIR = "src/structs"
+	GENERATE_COMMAND    = "generate"
+)
+
+type generateOptions struct {
+	dir        string
+	module     string
+	structsDir string
+	force      bool
+	impl       entity.ImplementationConfig
+	tags       entity.TagNaming
+	// verifier collects the generated files instead of writing them
+	// during the dry run of --verify.
+	verifier *verify.Verifier
+}
+
+func (o generateOptions) entityName(name string) entity.EntityName {
+	return entity.NewEntityName(name, o.structsDir, o.module)
+}
+
+func (o generateOptions) generatorConfig(cmd *cobra.Command) generator.Config {
+	return generator.Config{
+		Dir:            o.dir,
+		Module:         o.module,
+		StructsDir:     o.structsDir,
+		Implementation: o.impl,
+		Tags:           o.tags,
+		Flags:          cmd.Flags(),
+	}
+}
+
+func newGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     GENERATE_COMMAND,
+		Aliases: []string{"g"},
+		Short:   "Generate code for an entity",
+		Long: "Generate code for an entity.\n\n" +
+			"generate <name> <Entity> runs the " + plugin.PREFIX + "<name> executable found on PATH when <name> is\n" +
+			"not a generate subcommand, the arguments after -- being passed to it.",
+		Args: cobra.ArbitraryArgs,
+		RunE: runPlugin,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.String(DIR_FLAG, ".", "project root directory")
+	flags.String(MODULE_FLAG, "", "project module path (defaults to the module in go.mod)")
+	flags.String(STRUCTS_DIR_FLAG, DEFAULT_STRUCTS_DIR, "directory, relative to the module, holding entity structs")
+	flags.Bool(FORCE_FLAG, false, "overwrite existing files")
+	flags.Bool(VALUE_RECEIVERS_FLAG, false, "use value receivers instead of pointer receivers")
+	flags.Bool(NO_CONSTRUCTOR_FLAG, false, "do not generate a constructor")
+	flags.Bool(NO_ASSERTION_FLAG, false, "do not generate a compile-time interface assertion")
+	flags.Bool(FUNCTIONAL_OPTIONS_FLAG, false, "set the dependencies through functional options given to the constructor")
+	flags.StringArray(DEPENDENCY_FLAG, nil,
+		"dependency injected through the constructor, as name:type[:import/path][=default], the default applying with --"+FUNCTIONAL_OPTIONS_FLAG)
+	flags.StringArray(TAG_FLAG, nil,
+		"struct tag populated on the entity fields, as key[=strategy] with the strategy one of "+
+			strings.Join(entity.TagNamings(), ", ")+", e.g. db=snake, or validate for the required rule on the string, slice, map and pointer fields; "+
+			"set by this flag only, projects have no configuration file for it")
+	flags.Bool(VERIFY_FLAG, false, "type-check the generated code with the rest of the module before writing it")
+
+	for _, g := range generator.Generators() {
+		cmd.AddCommand(newGeneratorCommand(g))
+	}
+	// the commands not taking an entity name, out of the registry
+	cmd.AddCommand(
+		newGenerateOpenAPICommand(),
+		newGenerateEntityCommand(),
+	)
+	for _, sub := range cmd.Commands() {
+		sub.RunE = verified(sub.RunE)
+	}
+	cmd.RunE = verified(cmd.RunE)
+	return cmd
+}
+
+// runPlugin runs the generator of an unknown generate subcommand, executed
+// from PATH.
+func runPlugin(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	if len(args) == 1 || args[1] == "" {
+		return fmt.Errorf("expected the entity name after %s", args[0])
+	}
+	p, err := plugin.Find(args[0], args[2:], cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	return runGenerator(cmd, p, args[1])
+}
+
+// newGeneratorCommand exposes a registered generator as a subcommand taking
+// the entity name.
+func newGeneratorCommand(g generator.Generator) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  g.Name() + " <Entity>",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerator(cmd, g, args[0])
+		},
+	}
+	if d, ok := g.(generator.Describer); ok {
+		cmd.Short = d.Short()
+		cmd.Aliases = d.Aliases()
+	}
+	g.Flags(cmd.Flags())
+	return cmd
+}
+
+// runGenerator writes the files the generator produces for the entity name.
+func runGenerator(cmd *cobra.Command, g generator.Generator, name string) error {
+	opts, err := readGenerateOptions(cmd)
+	if err != nil {
+		return err
+	}
+	outputs, err := g.Generate(cmd.Context(), opts.entityName(name), opts.generatorConfig(cmd))
+	if err != nil {
+		return err
+	}
+	if err := writeOutputs(cmd, opts, outputs); err != nil {
+		return err
+	}
+	if routes, ok := g.(generator.RouteGenerator); ok && routes.GeneratesRoutes() {
+		return syncOpenAPI(cmd, opts)
+	}
+	return nil
+}
+
+// newGenerateEntityCommand generates the entities of a schema file. It is
+// not a registered generator as it takes a file rather than an entity name,
+// the entities it creates being named by the schema, and runs the registered
+// repository and service generators on each of them.
+func newGenerateEntityCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "entity <schema-file>",
+		Short: "Generate an entity struct with its repository, service and handler from a YAML or JSON schema",
+		Long: "Generate an entity struct with its repository, service and handler from a YAML or JSON schema,\n" +
+			"or one entity per table of the CREATE TABLE statements read with --" + FROM_SQL_FLAG + ".",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := readGenerateOptions(cmd)
+			if err != nil {
+				return err
+			}
+			flags := cmd.Flags()
+			framework, err := flags.GetString(FRAMEWORK_FLAG)
+			if err != nil {
+				return err
+			}
+			sqlFile, err := flags.GetString(FROM_SQL_FLAG)
+			if err != nil {
+				return err
+			}
+			nullable, err := flags.GetString(NULLABLE_FLAG)
+			if err != nil {
+				return err
+			}
+			dialect, err := flags.GetString(DIALECT_FLAG)
+			if err != nil {
+				return err
+			}
+
+			schemaFile := sqlFile
+			if len(args) == 1 {
+				schemaFile = args[0]
+			}
+			if (len(args) == 1) == (sqlFile != "") {
+				return fmt.Errorf("expected either a schema file or --%s", FROM_SQL_FLAG)
+			}
+			data, err := os.ReadFile(schemaFile)
+			if err != nil {
+				return err
+			}
+
+			var schemas []entity.EntitySchema
+			if sqlFile != "" {
+				var skipped []error
+				schemas, skipped, err = entity.ParseSQLSchemas(string(data), dialect, nullable)
+				for _, reason := range skipped {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s: %v\n", schemaFile, reason)
+				}
+			} else {
+				var schema entity.EntitySchema
+				schema, err = entity.ParseEntitySchema(data)
+				schemas = append(schemas, schema)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", schemaFile, err)
+			}
+
+			entities := make([]entity.Entity, 0, len(schemas))
+			for _, schema := range schemas {
+				e, err := entity.NewEntityWithTags(schema, opts.structsDir, opts.module, opts.tags)
+				if err != nil {
+					return fmt.Errorf("%s: %w", schemaFile, err)
+				}
+				if _, err := entity.NewHandlerWithFramework(e.Name(), opts.module, framework); err != nil {
+					return err
+				}
+				entities = append(entities, e)
+			}
+			for _, e := range entities {
+				if err := writeEntity(cmd, opts, e, framework); err != nil {
+					return err
+				}
+			}
+			return syncOpenAPI(cmd, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.String(FRAMEWORK_FLAG, entity.DEFAULT_HANDLER_FRAMEWORK,
+		"HTTP framework, one of "+strings.Join(entity.HandlerFrameworks(), ", "))
+	flags.String(FROM_SQL_FLAG, "", "read the entities from the CREATE TABLE statements of a PostgreSQL or MySQL file")
+	flags.String(DIALECT_FLAG, entity.DIALECT_POSTGRES,
+		"SQL dialect of --"+FROM_SQL_FLAG+", "+entity.DIALECT_POSTGRES+" or "+entity.DIALECT_MYSQL+" where # starts comments")
+	flags.String(NULLABLE_FLAG, entity.NULLABLE_POINTER,
+		"Go type of nullable SQL columns, "+entity.NULLABLE_POINTER+" or "+entity.NULLABLE_SQL+" for database/sql Null types")
+	return cmd
+}
+
+func writeEntity(cmd *cobra.Command, opts generateOptions, e entity.Entity, framework string) error {
+	if err := writeGenerated(cmd, opts, e.FilePath(), e.File()); err != nil {
+		return err
+	}
+	config := opts.generatorConfig(cmd)
+	for _, g := range []generator.Generator{builtin.Repository{}, builtin.Service{}} {
+		outputs, err := g.Generate(cmd.Context(), e.Name(), config)
+		if err != nil {
+			return err
+		}
+		if err := writeOutputs(cmd, opts, outputs); err != nil {
+			return err
+		}
+	}
+	outputs, err := builtin.HandlerOutputs(e.Name(), opts.module, framework)
+	if err != nil {
+		return err
+	}
+	return writeOutputs(cmd, opts, outputs)
+}
+
+// newGenerateOpenAPICommand generates the spec of the project. It is not a
+// registered generator as it describes the handlers of every entity rather
+// than the one of an entity name.
+func newGenerateOpenAPICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Generate an OpenAPI 3 spec describing the routes of every generated handler",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := readGenerateOptions(cmd)
+			if err != nil {
+				return err
+			}
+			mappings, err := cmd.Flags().GetStringArray(TYPE_MAP_FLAG)
+			if err != nil {
+				return err
+			}
+			types, err := schemaTypes(mappings)
+			if err != nil {
+				return err
+			}
+
+			spec, err := buildOpenAPI(opts, types)
+			if err != nil {
+				return err
+			}
+			return writeContent(cmd, opts, spec.FilePath(), []byte(spec.Document().String()))
+		},
+	}
+	cmd.Flags().StringArray(TYPE_MAP_FLAG, nil, "OpenAPI schema for a Go type, as GoType=type[:format]")
+	return cmd
+}
+
+// schemaTypes returns the default schema types overridden by the
+// GoType=type[:format] mappings.
+func schemaTypes(mappings []string) (entity.SchemaTypes, error) {
+	types := entity.DefaultSchemaTypes()
+	for _, raw := range mappings {
+		goType, schema, err := entity.ParseSchemaType(raw)
+		if err != nil {
+			return nil, err
+		}
+		types[goType] = schema
+	}
+	return types, nil
+}
+
+// buildOpenAPI describes every entity of the structs directory that already
+// has a generated handler.
+func buildOpenAPI(opts generateOptions, types entity.SchemaTypes) (entity.OpenAPI, error) {
+	structs, err := entity.LoadStructs(opts.dir, opts.structsDir)
+	if err != nil {
+		return entity.OpenAPI{}, err
+	}
+
+	resources := make([]entity.EntityName, 0)
+	for _, s := range structs {
+		structName := opts.entityName(s.Name)
+		handler := entity.NewHandler(structName, opts.module)
+		if _, err := os.Stat(filepath.Join(opts.dir, handler.FilePath())); err == nil {
+			resources = append(resources, structName)
+		}
+	}
+	if len(resources) == 0 {
+		return entity.OpenAPI{}, fmt.Errorf("no handlers found for the structs in %s, generate one first", opts.structsDir)
+	}
+
+	return entity.NewOpenAPI(path.Base(opts.module), opts.module, resources, structs, types)
+}
+
+// syncOpenAPI regenerates the OpenAPI spec, when the project has one, so it
+// describes the handlers just generated. The type mappings the spec was
+// generated with, recorded in the manifest, are applied again.
+func syncOpenAPI(cmd *cobra.Command, opts generateOptions) error {
+	if _, err := os.Stat(filepath.Join(opts.dir, entity.OPENAPI_PATH)); err != nil {
+		return nil
+	}
+	m, err := manifest.Load(opts.dir)
+	if err != nil {
+		return err
+	}
+	inputs := manifest.Inputs{Generator: "generate openapi"}
+	if entry, ok := m.Find(entity.OPENAPI_PATH); ok {
+		inputs.Options = entry.Inputs.Options
+	}
+	mappings := make([]string, 0)
+	prefix := "--" + TYPE_MAP_FLAG + "="
+	for _, option := range inputs.Options {
+		if strings.HasPrefix(option, prefix) {
+			mappings = append(mappings, strings.TrimPrefix(option, prefix))
+		}
+	}
+	types, err := schemaTypes(mappings)
+	if err != nil {
+		return err
+	}
+
+	spec, err := buildOpenAPI(opts, types)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "could not update %s, run generate openapi: %v\n", entity.OPENAPI_PATH, err)
+		return nil
+	}
+	// the spec is recorded as generated by generate openapi, whichever
+	// command refreshed it
+	opts.force = true
+	content := []byte(spec.Document().String())
+	if err := writeFile(cmd, opts, spec.FilePath(), content); err != nil {
+		return err
+	}
+	inputs.ToolVersion = toolVersion()
+	return record(opts, spec.FilePath(), content, inputs)
+}
+
+func readGenerateOptions(cmd *cobra.Command) (generateOptions, error) {
+	opts, err := readProjectOptions(cmd)
+	if err != nil {
+		return opts, err
+	}
+
+	flags := cmd.Flags()
+	valueReceivers, err := flags.GetBool(VALUE_RECEIVERS_FLAG)
+	if err != nil {
+		return opts, err
+	}
+	noConstructor, err := flags.GetBool(NO_CONSTRUCTOR_FLAG)
+	if err != nil {
+		return opts, err
+	}
+	noAssertion, err := flags.GetBool(NO_ASSERTION_FLAG)
+	if err != nil {
+		return opts, err
+	}
+	opts.impl.PointerReceivers = !valueReceivers
+	opts.impl.Constructor = !noConstructor
+	opts.impl.InterfaceAssertion = !noAssertion
+	if opts.impl.FunctionalOptions, err = flags.GetBool(FUNCTIONAL_OPTIONS_FLAG); err != nil {
+		return opts, err
+	}
+
+	dependencies, err := flags.GetStringArray(DEPENDENCY_FLAG)
+	if err != nil {
+		return opts, err
+	}
+	for _, raw := range dependencies {
+		dep, err := parseDependency(raw)
+		if err != nil {
+			return opts, err
+		}
+		opts.impl.Dependencies = append(opts.impl.Dependencies, dep)
+	}
+
+	tags, err := flags.GetStringArray(TAG_FLAG)
+	if err != nil {
+		return opts, err
+	}
+	for _, raw := range tags {
+		key, strategy, err := entity.ParseTagNaming(raw)
+		if err != nil {
+			return opts, err
+		}
+		opts.tags[key] = strategy
+	}
+
+	return opts, nil
+}
+
+// readProjectOptions reads the flags locating the project, shared by the
+// commands working on generated files.
+func readProjectOptions(cmd *cobra.Command) (generateOptions, error) {
+	flags := cmd.Flags()
+	opts := generateOptions{
+		impl: entity.DefaultImplementationConfig(),
+		tags: entity.DefaultTagNaming(),
+	}
+
+	var err error
+	if opts.dir, err = flags.GetString(DIR_FLAG); err != nil {
+		return opts, err
+	}
+	if opts.module, err = flags.GetString(MODULE_FLAG); err != nil {
+		return opts, err
+	}
+	if opts.module == "" {
+		if opts.module, err = utils.ModulePath(opts.dir); err != nil {
+			return opts, fmt.Errorf("could not resolve module path, use --%s: %w", MODULE_FLAG, err)
+		}
+	}
+	if opts.structsDir, err = flags.GetString(STRUCTS_DIR_FLAG); err != nil {
+		return opts, err
+	}
+	if opts.force, err = flags.GetBool(FORCE_FLAG); err != nil {
+		return opts, err
+	}
+	opts.verifier = commandVerifier(cmd)
+	return opts, nil
+}
+
+func parseDependency(raw string) (entity.Dependency, error) {
+	// types and import paths hold no =, the default may hold : instead
+	spec, value, _ := strings.Cut(raw, "=")
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return entity.Dependency{}, fmt.Errorf("invalid dependency %q, expected name:type[:import/path][=default]", raw)
+	}
+
+	dep := entity.Dependency{Name: parts[0], Type: parts[1], Default: value}
+	if len(parts) == 3 && parts[2] != "" {
+		dep.Import = file.Import{Path: parts[2]}
+	}
+	return dep, nil
+}
@@ -0,0 +1,49 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	t.Run("it should keep the type mappings of the spec when syncing it", func(t *testing.T) {
+		dir := writeProject(t, map[string]string{
+			"go.mod": "module github.com/e/svc\n\ngo 1.19\n",
+			"src/structs/xpto_struct.go": "package structs\n\n" +
+				"import \"time\"\n\n" +
+				"type XptoStruct struct {\n" +
+				"\tName      string    `json:\"name\"`\n" +
+				"\tCreatedAt time.Time `json:\"created_at\"`\n" +
+				"}\n",
+		})
+		mapping := "--type-map=time.Time=string:date"
+		for _, args := range [][]string{
+			{"generate", "handler", "XptoStruct"},
+			{"generate", "openapi", mapping},
+			{"generate", "handler", "XptoStruct", "--force"},
+		} {
+			if out, err := run(t, append(args, "--dir", dir)...); err != nil {
+				t.Fatalf("%s failed: %v\n%s", strings.Join(args, " "), err, out)
+			}
+		}
+
+		spec := readFile(t, dir, "openapi.yaml")
+		if !strings.Contains(spec, "format: date\n") || strings.Contains(spec, "date-time") {
+			t.Errorf("synced spec lost the type mapping:\n%s", spec)
+		}
+		m, err := manifest.Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		entry, ok := m.Find("openapi.yaml")
+		if !ok {
+			t.Fatalf("openapi.yaml is not recorded")
+		}
+		if got := strings.Join(entry.Inputs.Options, " "); !strings.Contains(got, mapping) {
+			utils.Error(t, mapping, got)
+		}
+	})
+}
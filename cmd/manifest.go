@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/spf13/cobra"
+)
+
+func newStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "List the generated files and whether they were modified since generated",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, m, err := loadManifest(cmd)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			for _, entry := range m.Files {
+				status, err := entry.Status(dir)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", status, entry.Path, commandLine(entry.Inputs))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().String(DIR_FLAG, ".", "project root directory")
+	return cmd
+}
+
+func newRegenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "regenerate",
+		Short: "Replay every generation recorded in the manifest, keeping the code written by users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, m, err := loadManifest(cmd)
+			if err != nil {
+				return err
+			}
+
+			failed := make([]string, 0)
+			for _, inputs := range m.Generations() {
+				fmt.Fprintf(cmd.OutOrStdout(), "running %s\n", commandLine(inputs))
+				// the flags go before the arguments, which may end with
+				// the ones passed through -- to a plugin
+				argv := append(strings.Fields(inputs.Generator), inputs.Options...)
+				argv = append(argv, "--"+DIR_FLAG+"="+dir)
+				argv = append(argv, inputs.Args...)
+
+				root := newRootCommand()
+				root.SetArgs(argv)
+				root.SetOut(cmd.OutOrStdout())
+				root.SetErr(cmd.ErrOrStderr())
+				if err := root.Execute(); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s failed: %v\n", commandLine(inputs), err)
+					failed = append(failed, inputs.Generator)
+				}
+			}
+			if len(failed) > 0 {
+				return fmt.Errorf("%d of %d generations failed", len(failed), len(m.Generations()))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(DIR_FLAG, ".", "project root directory")
+	return cmd
+}
+
+func newCleanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove the generated files that were not modified since generated",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, m, err := loadManifest(cmd)
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range append([]manifest.Entry(nil), m.Files...) {
+				status, err := entry.Status(dir)
+				if err != nil {
+					return err
+				}
+				switch status {
+				case manifest.STATUS_MODIFIED:
+					fmt.Fprintf(cmd.OutOrStdout(), "kept %s, modified since generated\n", entry.Path)
+					continue
+				case manifest.STATUS_UNCHANGED:
+					if err := removeFile(dir, entry.Path); err != nil {
+						return err
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", entry.Path)
+				}
+				m.Remove(entry.Path)
+			}
+			return m.Save(dir)
+		},
+	}
+	cmd.Flags().String(DIR_FLAG, ".", "project root directory")
+	return cmd
+}
+
+func loadManifest(cmd *cobra.Command) (string, manifest.Manifest, error) {
+	dir, err := cmd.Flags().GetString(DIR_FLAG)
+	if err != nil {
+		return "", manifest.Manifest{}, err
+	}
+	m, err := manifest.Load(dir)
+	return dir, m, err
+}
+
+// removeFile removes the file and the directories it leaves empty, up to
+// the project root.
+func removeFile(dir string, path string) error {
+	fullPath := filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.Remove(fullPath); err != nil {
+		return err
+	}
+	root := filepath.Clean(dir)
+	for parent := filepath.Dir(fullPath); parent != root && parent != "."; parent = filepath.Dir(parent) {
+		// removing a directory fails while it is not empty
+		if os.Remove(parent) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func commandLine(inputs manifest.Inputs) string {
+	parts := append([]string{"microcli", inputs.Generator}, inputs.Args...)
+	return strings.Join(append(parts, inputs.Options...), " ")
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/eduardoths/micro-cli/generator/regions"
+	"github.com/eduardoths/micro-cli/generator/rename"
+	"github.com/spf13/cobra"
+)
+
+const DRY_RUN_FLAG = "dry-run"
+
+// renamedFile is a project file rewritten or moved by a rename.
+type renamedFile struct {
+	from   string
+	to     string
+	before []byte
+	after  []byte
+}
+
+func (f renamedFile) moved() bool {
+	return f.from != f.to
+}
+
+func (f renamedFile) rewritten() bool {
+	return string(f.before) != string(f.after)
+}
+
+func newRenameCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <Old> <New>",
+		Short: "Rename an entity across the struct, repository, service and handler layers",
+		Long: "Rename an entity across the struct, repository, service and handler layers: files and\n" +
+			"directories named after it are moved, the identifiers declared in them are renamed\n" +
+			"along with their uses in every Go file of the project, and the import paths, routes\n" +
+			"and messages of the entity are rewritten. Identifiers of other packages, such as\n" +
+			"http.Request when renaming Request, and import aliases are kept.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := readProjectOptions(cmd)
+			if err != nil {
+				return err
+			}
+			dryRun, err := cmd.Flags().GetBool(DRY_RUN_FLAG)
+			if err != nil {
+				return err
+			}
+
+			structs, err := entity.LoadStructs(opts.dir, opts.structsDir)
+			if err != nil {
+				return err
+			}
+			others := make([]string, 0, len(structs))
+			for _, s := range structs {
+				others = append(others, s.Name)
+			}
+			r, err := rename.New(args[0], args[1], others)
+			if err != nil {
+				return err
+			}
+
+			files, err := planRename(opts, r)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no file refers to %s", r.From().PascalCase())
+			}
+			if dryRun {
+				previewRename(cmd, files)
+				return nil
+			}
+			if err := applyRename(cmd, opts, r, files); err != nil {
+				return err
+			}
+			return syncOpenAPI(cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.String(DIR_FLAG, ".", "project root directory")
+	flags.String(MODULE_FLAG, "", "project module path (defaults to the module in go.mod)")
+	flags.String(STRUCTS_DIR_FLAG, DEFAULT_STRUCTS_DIR, "directory, relative to the module, holding entity structs")
+	flags.Bool(FORCE_FLAG, false, "overwrite existing files at the new paths")
+	flags.Bool(DRY_RUN_FLAG, false, "print the files that would be moved and rewritten without changing them")
+	return cmd
+}
+
+// planRename computes the moves and rewrites of the Go and protobuf files of
+// the project.
+func planRename(opts generateOptions, r rename.Renamer) ([]renamedFile, error) {
+	sources, err := r.Sources(opts.dir, opts.module)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]renamedFile, 0)
+	err = filepath.WalkDir(opts.dir, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if fullPath != opts.dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(fullPath)
+		if ext != ".go" && ext != ".proto" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(opts.dir, fullPath)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(fullPath)
+		if err != nil {
+			return err
+		}
+		f := renamedFile{
+			from:   filepath.ToSlash(rel),
+			to:     r.Path(filepath.ToSlash(rel)),
+			before: src,
+			after:  src,
+		}
+		if rewritten, ok := sources[f.from]; ok {
+			f.after = rewritten
+		}
+		if regions.IsManaged(f.before) && f.rewritten() {
+			if f.after, err = regions.Restamp(f.before, f.after); err != nil {
+				return fmt.Errorf("%s: %w", f.from, err)
+			}
+		}
+		if f.moved() || f.rewritten() {
+			files = append(files, f)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func previewRename(cmd *cobra.Command, files []renamedFile) {
+	out := cmd.OutOrStdout()
+	for _, f := range files {
+		if f.moved() {
+			fmt.Fprintf(out, "move %s -> %s\n", f.from, f.to)
+		}
+		if !f.rewritten() {
+			continue
+		}
+		fmt.Fprintf(out, "rewrite %s\n", f.to)
+		before := strings.Split(string(f.before), "\n")
+		after := strings.Split(string(f.after), "\n")
+		if len(before) != len(after) {
+			continue
+		}
+		for i := range before {
+			if before[i] != after[i] {
+				fmt.Fprintf(out, "\t-%s\n\t+%s\n", before[i], after[i])
+			}
+		}
+	}
+}
+
+// applyRename writes the renamed files, removes the moved ones and updates
+// the manifest. No file is changed when a new path is taken, unless forced.
+func applyRename(cmd *cobra.Command, opts generateOptions, r rename.Renamer, files []renamedFile) error {
+	moving := make(map[string]bool, len(files))
+	for _, f := range files {
+		moving[f.from] = true
+	}
+	for _, f := range files {
+		if !f.moved() || moving[f.to] || opts.force {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(opts.dir, f.to)); err == nil {
+			return fmt.Errorf("%s already exists, use --%s to overwrite it", f.to, FORCE_FLAG)
+		}
+	}
+
+	m, err := manifest.Load(opts.dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		fullPath := filepath.Join(opts.dir, filepath.FromSlash(f.to))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, f.after, 0o644); err != nil {
+			return err
+		}
+		if f.moved() {
+			if err := removeFile(opts.dir, f.from); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "moved %s -> %s\n", f.from, f.to)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "updated %s\n", f.to)
+		}
+		renameEntry(&m, r, f)
+	}
+	return m.Save(opts.dir)
+}
+
+// renameEntry moves the manifest entry of the file, keeping it generated
+// when it was not modified before the rename.
+func renameEntry(m *manifest.Manifest, r rename.Renamer, f renamedFile) {
+	entry, ok := m.Find(f.from)
+	if !ok {
+		return
+	}
+	m.Remove(f.from)
+	if entry.Hash == manifest.Hash(f.before) {
+		entry.Hash = manifest.Hash(f.after)
+	}
+	entry.Path = f.to
+	for i, arg := range entry.Inputs.Args {
+		if arg == r.From().PascalCase() {
+			entry.Inputs.Args[i] = r.To().PascalCase()
+		}
+	}
+	m.Record(entry)
+}
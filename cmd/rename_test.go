@@ -0,0 +1,99 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+const (
+	requestStruct  = "src/structs/request.go"
+	requestHandler = "src/handlers/request/request_handler.go"
+	ticketStruct   = "src/structs/ticket.go"
+	ticketHandler  = "src/handlers/ticket/ticket_handler.go"
+)
+
+func requestProject(t *testing.T) string {
+	t.Helper()
+	return writeProject(t, map[string]string{
+		"go.mod": "module github.com/e/svc\n\ngo 1.19\n",
+		requestStruct: "package structs\n\n" +
+			"type Request struct {\n" +
+			"\tName string\n" +
+			"}\n",
+		requestHandler: "package request\n\n" +
+			"import (\n" +
+			"\t\"net/http\"\n\n" +
+			"\t\"github.com/e/svc/src/structs\"\n" +
+			")\n\n" +
+			"type RequestHandler struct {\n" +
+			"\tlast structs.Request\n" +
+			"}\n\n" +
+			"func (rh *RequestHandler) Get(w http.ResponseWriter, r *http.Request) {\n" +
+			"\trh.last = structs.Request{Name: r.URL.Path}\n" +
+			"}\n",
+	})
+}
+
+func TestRename(t *testing.T) {
+	t.Run("it should print the rename without applying it on dry runs", func(t *testing.T) {
+		dir := requestProject(t)
+		before := readFile(t, dir, requestHandler)
+
+		out, err := run(t, "rename", "Request", "Ticket", "--dir", dir, "--dry-run")
+		if err != nil {
+			t.Fatalf("rename failed: %v", err)
+		}
+		for _, want := range []string{
+			"move " + requestHandler + " -> " + ticketHandler,
+			"move " + requestStruct + " -> " + ticketStruct,
+			"+type TicketHandler struct {",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("rename output misses %q:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "http.Ticket") {
+			t.Errorf("rename renamed http.Request:\n%s", out)
+		}
+		if got := readFile(t, dir, requestHandler); got != before {
+			utils.Error(t, before, got)
+		}
+		if exists(dir, ticketHandler) {
+			t.Errorf("dry run wrote %s", ticketHandler)
+		}
+	})
+
+	t.Run("it should rename the entity keeping the identifiers of imported packages", func(t *testing.T) {
+		dir := requestProject(t)
+
+		if _, err := run(t, "rename", "Request", "Ticket", "--dir", dir); err != nil {
+			t.Fatalf("rename failed: %v", err)
+		}
+		if exists(dir, requestHandler) || exists(dir, requestStruct) {
+			t.Errorf("rename left the files of Request")
+		}
+		want := "package ticket\n\n" +
+			"import (\n" +
+			"\t\"net/http\"\n\n" +
+			"\t\"github.com/e/svc/src/structs\"\n" +
+			")\n\n" +
+			"type TicketHandler struct {\n" +
+			"\tlast structs.Ticket\n" +
+			"}\n\n" +
+			"func (th *TicketHandler) Get(w http.ResponseWriter, r *http.Request) {\n" +
+			"\tth.last = structs.Ticket{Name: r.URL.Path}\n" +
+			"}\n"
+		if got := readFile(t, dir, ticketHandler); got != want {
+			utils.Error(t, want, got)
+		}
+		wantStruct := "package structs\n\n" +
+			"type Ticket struct {\n" +
+			"\tName string\n" +
+			"}\n"
+		if got := readFile(t, dir, ticketStruct); got != wantStruct {
+			utils.Error(t, wantStruct, got)
+		}
+	})
+}
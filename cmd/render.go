@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/spf13/cobra"
+)
+
+func newRenderCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "render [model.json]",
+		Short: "Print the Go source of a JSON file model, read from stdin without a file or with -",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if len(args) == 0 || args[0] == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			var f file.File
+			if err := json.Unmarshal(data, &f); err != nil {
+				return fmt.Errorf("invalid file model: %w", err)
+			}
+			src, err := format.Source([]byte(f.String()))
+			if err != nil {
+				return fmt.Errorf("the file model renders invalid code: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(src)
+			return err
+		},
+	}
+}
@@ -8,18 +8,40 @@ import (
 
 var buildVersion string
 
-const VERSION_FLAG = "version"
+const (
+	VERSION_FLAG = "version"
+	DEV_VERSION  = "dev"
+)
 
 func newRootCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "microcli",
-		Long:    "Microservices tool",
-		Version: buildVersion,
-		Run:     defaultCommand,
+		Use:           "microcli",
+		Long:          "Microservices tool",
+		Version:       buildVersion,
+		Run:           defaultCommand,
+		SilenceUsage:  true,
+		SilenceErrors: true,
 	}
+	cmd.AddCommand(
+		newGenerateCommand(),
+		newDestroyCommand(),
+		newRenameCommand(),
+		newStatusCommand(),
+		newRegenerateCommand(),
+		newCleanCommand(),
+		newRenderCommand(),
+	)
 	return cmd
 }
 
+// toolVersion is the version recorded along with the generated files.
+func toolVersion() string {
+	if buildVersion == "" {
+		return DEV_VERSION
+	}
+	return buildVersion
+}
+
 func defaultCommand(cmd *cobra.Command, args []string) {
 	if err := cmd.Help(); err != nil {
 		log.Fatalf("Something unexpected happened %s", err.Error())
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eduardoths/micro-cli/generator/verify"
+	"github.com/spf13/cobra"
+)
+
+const VERIFY_FLAG = "verify"
+
+type verifierKey struct{}
+
+// verified runs the generator twice with --verify: first with the files
+// overlaid in memory and type-checked with the rest of the module, then for
+// real once they check.
+func verified(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		enabled, err := cmd.Flags().GetBool(VERIFY_FLAG)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return run(cmd, args)
+		}
+
+		opts, err := readProjectOptions(cmd)
+		if err != nil {
+			return err
+		}
+		v, err := verify.New(opts.dir, opts.module)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		cmd.SetContext(context.WithValue(ctx, verifierKey{}, v))
+		err = run(cmd, args)
+		cmd.SetContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := v.Check(); err != nil {
+			return fmt.Errorf("nothing was written: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "verified %d Go files\n", len(v.Files()))
+		return run(cmd, args)
+	}
+}
+
+// commandVerifier returns the verifier of the dry run of cmd, if any.
+func commandVerifier(cmd *cobra.Command) *verify.Verifier {
+	if cmd.Context() == nil {
+		return nil
+	}
+	v, _ := cmd.Context().Value(verifierKey{}).(*verify.Verifier)
+	return v
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/eduardoths/micro-cli/generator/merge"
+	"github.com/eduardoths/micro-cli/generator/regions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func writeGenerated(cmd *cobra.Command, opts generateOptions, path string, f file.File) error {
+	return writeSource(cmd, opts, path, []byte(f.String()), func(existing []byte) error {
+		return mergeMissing(cmd, opts, path, f, existing)
+	})
+}
+
+// writeSource formats and stamps the Go source before writing it to path,
+// keeping the user regions of the file written by a previous generation.
+// Other existing files are handed to merge, or left untouched when it is nil.
+func writeSource(cmd *cobra.Command, opts generateOptions, path string, src []byte, merge func(existing []byte) error) error {
+	src, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("generated invalid code for %s: %w", path, err)
+	}
+	if src, err = regions.Stamp(src); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	existing, err := os.ReadFile(filepath.Join(opts.dir, path))
+	if err != nil || opts.force {
+		return writeContent(cmd, opts, path, src)
+	}
+
+	// files written by microcli are regenerated keeping the user regions,
+	// the others only get the declarations they lack
+	opts.force = true
+	if regions.IsManaged(existing) {
+		merged, err := regions.Merge(src, existing)
+		if err == nil {
+			if err := writeFile(cmd, opts, path, merged); err != nil {
+				return err
+			}
+			return record(opts, path, src, generationInputs(cmd))
+		}
+		if !errors.Is(err, regions.ErrConflict) {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if merge == nil {
+			return fmt.Errorf("%s: %w, use --%s to overwrite it", path, err, FORCE_FLAG)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s: %v, adding the missing declarations only\n", path, err)
+	}
+	if merge == nil {
+		return fmt.Errorf("%s already exists, use --%s to overwrite it", path, FORCE_FLAG)
+	}
+	if err := merge(existing); err != nil {
+		return err
+	}
+	return record(opts, path, src, generationInputs(cmd))
+}
+
+// writeOutputs writes the files produced by a generator.
+func writeOutputs(cmd *cobra.Command, opts generateOptions, outputs []generator.Output) error {
+	for _, out := range outputs {
+		var err error
+		switch {
+		case out.IsGo():
+			err = writeGenerated(cmd, opts, out.Path, out.File)
+		case filepath.Ext(out.Path) == ".go":
+			err = writeSource(cmd, opts, out.Path, out.Content, nil)
+		default:
+			err = writeContent(cmd, opts, out.Path, out.Content)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeMissing adds to the existing file the declarations of f it lacks.
+func mergeMissing(cmd *cobra.Command, opts generateOptions, path string, f file.File, existing []byte) error {
+	merged, added, err := merge.File(f, existing)
+	if err != nil {
+		return fmt.Errorf("%s: %w, use --%s to overwrite it", path, err, FORCE_FLAG)
+	}
+	if opts.verifier != nil {
+		opts.verifier.Add(path, merged)
+		return nil
+	}
+	if len(added) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "unchanged %s\n", filepath.Clean(path))
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(opts.dir, path), merged, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "merged %s (added %s)\n", filepath.Clean(path), strings.Join(added, ", "))
+	return nil
+}
+
+// writeContent writes src to path and records it in the manifest. Files left
+// as generated are overwritten, so that non Go files are regenerated too.
+func writeContent(cmd *cobra.Command, opts generateOptions, path string, src []byte) error {
+	if existing, err := os.ReadFile(filepath.Join(opts.dir, path)); err == nil && !opts.force {
+		m, err := manifest.Load(opts.dir)
+		if err != nil {
+			return err
+		}
+		opts.force = generated(m, path, existing)
+	}
+	if err := writeFile(cmd, opts, path, src); err != nil {
+		return err
+	}
+	return record(opts, path, src, generationInputs(cmd))
+}
+
+func writeFile(cmd *cobra.Command, opts generateOptions, path string, src []byte) error {
+	fullPath := filepath.Join(opts.dir, path)
+	action := "created"
+	if _, err := os.Stat(fullPath); err == nil {
+		if !opts.force {
+			return fmt.Errorf("%s already exists, use --%s to overwrite it", path, FORCE_FLAG)
+		}
+		action = "updated"
+		if existing, err := os.ReadFile(fullPath); err == nil && bytes.Equal(existing, src) && opts.verifier == nil {
+			fmt.Fprintf(cmd.OutOrStdout(), "unchanged %s\n", filepath.Clean(path))
+			return nil
+		}
+	}
+	if opts.verifier != nil {
+		opts.verifier.Add(path, src)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fullPath, src, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", action, filepath.Clean(path))
+	return nil
+}
+
+// record adds the file to the manifest of the project, along with the hash
+// of its generated content and the inputs that generated it.
+func record(opts generateOptions, path string, generated []byte, inputs manifest.Inputs) error {
+	if opts.verifier != nil {
+		return nil
+	}
+	m, err := manifest.Load(opts.dir)
+	if err != nil {
+		return err
+	}
+	m.Record(manifest.Entry{
+		Path:   path,
+		Hash:   manifest.Hash(generated),
+		Inputs: inputs,
+	})
+	return m.Save(opts.dir)
+}
+
+// generationInputs reads the command line of cmd, leaving out the flags
+// that do not change the generated content.
+func generationInputs(cmd *cobra.Command) manifest.Inputs {
+	inputs := manifest.Inputs{
+		Generator:   strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Args:        commandArgs(cmd),
+		ToolVersion: toolVersion(),
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name == DIR_FLAG || flag.Name == FORCE_FLAG || flag.Name == VERIFY_FLAG {
+			return
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range slice.GetSlice() {
+				inputs.Options = append(inputs.Options, "--"+flag.Name+"="+value)
+			}
+			return
+		}
+		inputs.Options = append(inputs.Options, "--"+flag.Name+"="+flag.Value.String())
+	})
+	return inputs
+}
+
+// commandArgs returns the arguments of cmd, along with the -- before the
+// ones given after it.
+func commandArgs(cmd *cobra.Command) []string {
+	args := cmd.Flags().Args()
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 {
+		return args
+	}
+	return append(append(append([]string(nil), args[:dash]...), "--"), args[dash:]...)
+}
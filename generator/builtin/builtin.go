@@ -0,0 +1,233 @@
+// Package builtin registers the generators shipped with microcli.
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator"
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FRAMEWORK_FLAG = "framework"
+	TYPE_MAP_FLAG  = "type-map"
+	FROM_JSON_FLAG = "from-json"
+	KIND_FLAG      = "kind"
+	LAYER_FLAG     = "layer"
+)
+
+func init() {
+	generator.MustRegister(Repository{})
+	generator.MustRegister(Service{})
+	generator.MustRegister(Handler{})
+	generator.MustRegister(Proto{})
+	generator.MustRegister(Struct{})
+	generator.MustRegister(Decorator{})
+}
+
+// Repository generates the repository of an entity.
+type Repository struct{}
+
+func (Repository) Name() string { return "repository" }
+
+func (Repository) Short() string { return "Generate a repository for an entity" }
+
+func (Repository) Aliases() []string { return []string{"repo"} }
+
+func (Repository) Flags(flags *pflag.FlagSet) {}
+
+func (Repository) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	repo := entity.NewRepositoryWithConfig(name, config.Module, config.Implementation)
+	return []generator.Output{
+		generator.GoFile(repo.FilePath(), repo.File()),
+	}, nil
+}
+
+// Service generates the service of an entity, on top of its repository, and
+// its tests.
+type Service struct{}
+
+func (Service) Name() string { return "service" }
+
+func (Service) Short() string { return "Generate a service for an entity on top of its repository" }
+
+func (Service) Aliases() []string { return []string{"svc"} }
+
+func (Service) Flags(flags *pflag.FlagSet) {}
+
+func (Service) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	service := entity.NewServiceWithConfig(name, config.Module, config.Implementation)
+	return []generator.Output{
+		generator.GoFile(service.FilePath(), service.File()),
+		generator.GoFile(service.TestFilePath(), service.TestFile()),
+	}, nil
+}
+
+// Handler generates the HTTP handler of an entity, on top of its service,
+// and its tests.
+type Handler struct{}
+
+func (Handler) Name() string { return "handler" }
+
+func (Handler) Short() string { return "Generate an HTTP handler for an entity on top of its service" }
+
+func (Handler) Aliases() []string { return nil }
+
+func (Handler) GeneratesRoutes() bool { return true }
+
+func (Handler) Flags(flags *pflag.FlagSet) {
+	flags.String(FRAMEWORK_FLAG, entity.DEFAULT_HANDLER_FRAMEWORK,
+		"HTTP framework, one of "+strings.Join(entity.HandlerFrameworks(), ", "))
+}
+
+func (Handler) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	framework, err := config.Flags.GetString(FRAMEWORK_FLAG)
+	if err != nil {
+		return nil, err
+	}
+	return HandlerOutputs(name, config.Module, framework)
+}
+
+// HandlerOutputs returns the handler of the entity for the framework and its
+// tests.
+func HandlerOutputs(name entity.EntityName, module string, framework string) ([]generator.Output, error) {
+	handler, err := entity.NewHandlerWithFramework(name, module, framework)
+	if err != nil {
+		return nil, err
+	}
+	return []generator.Output{
+		generator.GoFile(handler.FilePath(), handler.File()),
+		generator.GoFile(handler.TestFilePath(), handler.TestFile()),
+	}, nil
+}
+
+// Proto generates the protobuf CRUD service of an entity, from the fields of
+// its struct, and its gRPC server adapter.
+type Proto struct{}
+
+func (Proto) Name() string { return "proto" }
+
+func (Proto) Short() string {
+	return "Generate a protobuf CRUD service for an entity and its gRPC server adapter"
+}
+
+func (Proto) Aliases() []string { return nil }
+
+func (Proto) Flags(flags *pflag.FlagSet) {
+	flags.StringArray(TYPE_MAP_FLAG, nil,
+		"protobuf mapping for a Go type, as GoType=protoType[;toProto;fromProto[;fallible]]")
+}
+
+func (Proto) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	types := entity.DefaultProtoTypes()
+	mappings, err := config.Flags.GetStringArray(TYPE_MAP_FLAG)
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range mappings {
+		goType, protoType, err := entity.ParseProtoType(raw)
+		if err != nil {
+			return nil, err
+		}
+		types[goType] = protoType
+	}
+
+	entityStruct, err := entity.LoadStruct(config.Dir, name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := entity.NewProto(name, config.Module, entityStruct, types)
+	if err != nil {
+		return nil, err
+	}
+	return []generator.Output{
+		generator.RawFile(p.ProtoFilePath(), p.ProtoFile().String()),
+		generator.GoFile(p.FilePath(), p.File()),
+	}, nil
+}
+
+// Struct generates a struct tree inferred from sample JSON payloads.
+type Struct struct{}
+
+func (Struct) Name() string { return "struct" }
+
+func (Struct) Short() string { return "Generate a struct tree inferred from sample JSON payloads" }
+
+func (Struct) Aliases() []string { return nil }
+
+func (Struct) Flags(flags *pflag.FlagSet) {
+	flags.StringArray(FROM_JSON_FLAG, nil, "sample JSON payload, repeat it to widen the inferred types")
+}
+
+func (Struct) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	paths, err := config.Flags.GetStringArray(FROM_JSON_FLAG)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("expected at least one --%s sample", FROM_JSON_FLAG)
+	}
+
+	samples := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		sample, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	s, err := entity.NewJSONStructWithTags(name, samples, config.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return []generator.Output{generator.GoFile(s.FilePath(), s.File())}, nil
+}
+
+// Decorator generates a wrapper of the repository or service interface of an
+// entity, read from its package, logging, measuring or tracing the calls of
+// its methods.
+type Decorator struct{}
+
+func (Decorator) Name() string { return "decorator" }
+
+func (Decorator) Short() string {
+	return "Generate a logging, metrics or tracing decorator of the repository or service of an entity"
+}
+
+func (Decorator) Aliases() []string { return nil }
+
+func (Decorator) Flags(flags *pflag.FlagSet) {
+	flags.String(KIND_FLAG, entity.DEFAULT_DECORATOR_KIND,
+		"decorator kind, one of "+strings.Join(entity.DecoratorKinds(), ", "))
+	flags.String(LAYER_FLAG, entity.REPOSITORY_LAYER,
+		"layer of the decorated interface, one of "+strings.Join(entity.Layers(), ", "))
+}
+
+func (Decorator) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	kind, err := config.Flags.GetString(KIND_FLAG)
+	if err != nil {
+		return nil, err
+	}
+	layer, err := config.Flags.GetString(LAYER_FLAG)
+	if err != nil {
+		return nil, err
+	}
+
+	iface, err := entity.LayerInterface(name, config.Module, layer)
+	if err != nil {
+		return nil, err
+	}
+	decorated, imports, err := entity.LoadInterface(config.Dir, iface)
+	if err != nil {
+		return nil, err
+	}
+	d, err := entity.NewDecorator(iface, decorated, imports, kind)
+	if err != nil {
+		return nil, err
+	}
+	return []generator.Output{generator.GoFile(d.FilePath(), d.File())}, nil
+}
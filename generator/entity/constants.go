@@ -0,0 +1,19 @@
+package entity
+
+const (
+	REPOSITORIES_PATH = "src/repositories"
+	SERVICES_PATH     = "src/services"
+	HANDLERS_PATH     = "src/handlers"
+	GRPC_PATH         = "src/grpc"
+	PROTO_PATH        = "proto"
+	OPENAPI_PATH      = "openapi.yaml"
+
+	ERRORS_PKG = "errors"
+
+	CONTEXT_PKG  = "context"
+	CONTEXT_TYPE = "context.Context"
+
+	ID_PKG        = "github.com/google/uuid"
+	ID_TYPE       = "uuid.UUID"
+	ID_PARSE_FUNC = "uuid.Parse"
+)
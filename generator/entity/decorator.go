@@ -0,0 +1,334 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+const (
+	DEFAULT_DECORATOR_KIND = "logging"
+
+	REPOSITORY_LAYER = "repository"
+	SERVICE_LAYER    = "service"
+)
+
+var (
+	ErrUnknownDecoratorKind = errors.New("unknown decorator kind")
+	ErrUnknownLayer         = errors.New("unknown layer")
+)
+
+// decoratorRenderer renders the kind specific parts of a Decorator: the
+// dependencies it is given besides the decorated interface and the body of
+// its methods around the call of the decorated one.
+type decoratorRenderer interface {
+	Fields(d Decorator) []file.Field
+	Imports() file.Imports
+	Interfaces(d Decorator) []file.Interface
+	Body(m decoratedMethod) file.Block
+}
+
+var decoratorRenderers = map[string]decoratorRenderer{
+	DEFAULT_DECORATOR_KIND: loggingDecorator{},
+	"metrics":              metricsDecorator{},
+	"tracing":              tracingDecorator{},
+}
+
+func DecoratorKinds() []string {
+	kinds := make([]string, 0, len(decoratorRenderers))
+	for kind := range decoratorRenderers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func Layers() []string {
+	return []string{REPOSITORY_LAYER, SERVICE_LAYER}
+}
+
+// LayerInterface returns the name of the interface generated for the entity
+// in the layer, one of Layers.
+func LayerInterface(structName EntityName, basePkg string, layer string) (EntityName, error) {
+	switch layer {
+	case REPOSITORY_LAYER:
+		return repositoryName(structName, basePkg), nil
+	case SERVICE_LAYER:
+		return serviceName(structName, basePkg), nil
+	}
+	return EntityName{}, fmt.Errorf("%w: %q, expected one of %s",
+		ErrUnknownLayer, layer, strings.Join(Layers(), ", "))
+}
+
+// Decorator wraps an interface in a struct implementing it, which calls the
+// wrapped implementation from each method and logs, measures or traces the
+// call depending on its kind.
+type Decorator struct {
+	iface      EntityName
+	decorator  EntityName
+	renderer   decoratorRenderer
+	methods    []file.Method
+	implStruct file.Struct
+
+	Interfaces []file.Interface
+	Imports    file.Imports
+	Vars       []file.Var
+}
+
+// NewDecorator returns the decorator of the kind for the interface named
+// iface, declaring the methods and referring to the imports.
+func NewDecorator(iface EntityName, decorated file.Interface, imports file.Imports, kind string) (Decorator, error) {
+	renderer, ok := decoratorRenderers[kind]
+	if !ok {
+		return Decorator{}, fmt.Errorf("%w: %q, expected one of %s",
+			ErrUnknownDecoratorKind, kind, strings.Join(DecoratorKinds(), ", "))
+	}
+
+	d := Decorator{
+		iface:     iface,
+		decorator: decoratorName(iface, kind),
+		renderer:  renderer,
+		methods:   decorated.MethodSet(),
+	}
+	d.build(imports)
+	return d, nil
+}
+
+// DecoratorPath returns the path of the decorator of the kind for the
+// interface named iface.
+func DecoratorPath(iface EntityName, kind string) (string, error) {
+	if _, ok := decoratorRenderers[kind]; !ok {
+		return "", fmt.Errorf("%w: %q, expected one of %s",
+			ErrUnknownDecoratorKind, kind, strings.Join(DecoratorKinds(), ", "))
+	}
+	return decoratorName(iface, kind).FilePath(), nil
+}
+
+func decoratorName(iface EntityName, kind string) EntityName {
+	return NewEntityName(NewEntityName(kind, "", "").PascalCase()+iface.PascalCase(), iface.dirPath, iface.basePkg)
+}
+
+func (d *Decorator) build(imports file.Imports) {
+	d.Imports = append(append(file.Imports{}, imports...), d.renderer.Imports()...)
+	d.Interfaces = d.renderer.Interfaces(*d)
+	d.Vars = []file.Var{{Name: "_", Type: d.iface.PascalCase(), Value: "(" + d.receiverName() + ")(nil)"}}
+
+	d.implStruct = file.Struct{
+		Name:   d.structName(),
+		Fields: append([]file.Field{{Name: "next", Type: d.iface.PascalCase()}}, d.renderer.Fields(*d)...),
+	}
+	d.implStruct.Implementations = append(d.implStruct.Implementations, d.constructor())
+	for _, m := range d.methods {
+		decorated := d.decorate(m)
+		d.implStruct.Implementations = append(d.implStruct.Implementations, file.Implementation{
+			StructAlias: d.alias(),
+			StructName:  d.receiverName(),
+			Func:        decorated.Method,
+			Body:        d.renderer.Body(decorated),
+			UserRegion:  true,
+		})
+	}
+}
+
+func (d Decorator) File() file.File {
+	return file.File{
+		Package:    d.iface.ImportName(),
+		Imports:    d.Imports,
+		Vars:       d.Vars,
+		Interfaces: d.Interfaces,
+		Structs:    []file.Struct{d.implStruct},
+	}
+}
+
+func (d Decorator) FilePath() string {
+	return d.decorator.FilePath()
+}
+
+func (d Decorator) structName() string {
+	return d.decorator.CamelCase()
+}
+
+func (d Decorator) receiverName() string {
+	return "*" + d.structName()
+}
+
+func (d Decorator) alias() string {
+	return d.decorator.Alias()
+}
+
+// constructor returns the constructor wrapping next, taking the
+// dependencies of the kind.
+func (d Decorator) constructor() file.Implementation {
+	params := file.Args{}
+	codeLines := []string{"return &" + d.structName() + "{"}
+	for _, field := range d.implStruct.Fields {
+		params = append(params, file.Arg{Name: field.Name, Type: field.Type})
+		codeLines = append(codeLines, "\t"+field.Name+": "+field.Name+",")
+	}
+	codeLines = append(codeLines, "}")
+
+	return file.Implementation{
+		Func: file.Method{
+			Name:    "New" + d.decorator.PascalCase(),
+			Params:  params,
+			Results: file.Args{{Type: d.iface.PascalCase()}},
+		},
+		CodeLines: codeLines,
+	}
+}
+
+// bodyPackages are the names of the packages the decorator bodies refer to,
+// which the args of the methods must not shadow.
+var bodyPackages = map[string]bool{
+	"context":   true,
+	"time":      true,
+	"slog":      true,
+	"trace":     true,
+	"attribute": true,
+	"codes":     true,
+}
+
+// decoratedMethod is a method of the decorated interface, its params and
+// results named so that the decorator forwards and returns them.
+type decoratedMethod struct {
+	file.Method
+	iface string
+	recv  string
+	taken map[string]bool
+}
+
+func (d Decorator) decorate(m file.Method) decoratedMethod {
+	dm := decoratedMethod{
+		Method: file.Method{Name: m.Name, Grouped: m.Grouped},
+		iface:  d.iface.PascalCase(),
+		recv:   d.alias(),
+		taken:  map[string]bool{d.alias(): true},
+	}
+	for pkg := range bodyPackages {
+		dm.taken[pkg] = true
+	}
+	for _, args := range []file.Args{m.Params, m.Results} {
+		for _, arg := range args {
+			if arg.Name != "" && arg.Name != "_" {
+				dm.taken[arg.Name] = true
+			}
+		}
+	}
+
+	named := func(args file.Args, base func(i int, arg file.Arg) string) file.Args {
+		result := make(file.Args, 0, len(args))
+		for i, arg := range args {
+			if arg.Name == "" || arg.Name == "_" || arg.Name == dm.recv || bodyPackages[arg.Name] {
+				arg.Name = dm.local(base(i, arg))
+				dm.taken[arg.Name] = true
+			}
+			result = append(result, arg)
+		}
+		return result
+	}
+	dm.Params = named(m.Params, func(i int, arg file.Arg) string {
+		if arg.Type == CONTEXT_TYPE {
+			return "ctx"
+		}
+		return "arg" + strconv.Itoa(i)
+	})
+	dm.Results = named(m.Results, func(i int, arg file.Arg) string {
+		if arg.Type == "error" {
+			return "err"
+		}
+		return "result" + strconv.Itoa(i)
+	})
+	return dm
+}
+
+// local returns a name for a local variable of the method, suffixed when
+// the method already uses base.
+func (m decoratedMethod) local(base string) string {
+	return localName(base, m.taken)
+}
+
+// localName returns base, suffixed when it is taken.
+func localName(base string, taken map[string]bool) string {
+	name := base
+	for i := 2; taken[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	return name
+}
+
+// qualifiedName names the method after its interface, as in
+// XptoRepository.Get, in the logs, metrics and spans.
+func (m decoratedMethod) qualifiedName() file.Expr {
+	return file.Code(strconv.Quote(m.iface + "." + m.Name))
+}
+
+// context returns the context param of the method, or a background context
+// when it takes none.
+func (m decoratedMethod) context() (file.Expr, bool) {
+	for _, param := range m.Params {
+		if param.Type == CONTEXT_TYPE {
+			return file.Code(param.Name), true
+		}
+	}
+	return file.Qual(file.Import{Path: CONTEXT_PKG}, "Background").Call(), false
+}
+
+// err returns the error result of the method, empty when it has none.
+func (m decoratedMethod) err() string {
+	for i := len(m.Results) - 1; i >= 0; i-- {
+		if m.Results[i].Type == "error" {
+			return m.Results[i].Name
+		}
+	}
+	return ""
+}
+
+// args returns the params of the method worth reporting, all but its
+// context.
+func (m decoratedMethod) args() file.Args {
+	args := make(file.Args, 0, len(m.Params))
+	for _, param := range m.Params {
+		if param.Type != CONTEXT_TYPE {
+			args = append(args, param)
+		}
+	}
+	return args
+}
+
+// call returns the call of the decorated method, assigning its results.
+func (m decoratedMethod) call() file.Stmt {
+	args := make([]file.Expr, 0, len(m.Params))
+	for _, param := range m.Params {
+		if param.Variadic {
+			args = append(args, file.Code(param.Name+"..."))
+			continue
+		}
+		args = append(args, file.Code(param.Name))
+	}
+	call := file.Code(m.recv + ".next." + m.Name).Call(args...)
+	if len(m.Results) == 0 {
+		return file.Eval(call)
+	}
+	return file.Assign(m.resultNames(), call)
+}
+
+// returns returns the statement returning the results of the decorated
+// method, none when it has no results.
+func (m decoratedMethod) returns() file.Block {
+	if len(m.Results) == 0 {
+		return nil
+	}
+	return file.Block{file.Line("return " + m.resultNames())}
+}
+
+func (m decoratedMethod) resultNames() string {
+	names := make([]string, 0, len(m.Results))
+	for _, result := range m.Results {
+		names = append(names, result.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"strconv"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+var (
+	slogImport = file.Import{Path: "log/slog"}
+	timeImport = file.Import{Path: "time"}
+)
+
+// loggingDecorator logs every call with log/slog, along with its args and
+// duration, at the error level when it fails.
+type loggingDecorator struct{}
+
+func (loggingDecorator) Fields(d Decorator) []file.Field {
+	return []file.Field{{Name: "logger", Type: "*slog.Logger"}}
+}
+
+func (loggingDecorator) Imports() file.Imports {
+	return file.Imports{slogImport}
+}
+
+func (loggingDecorator) Interfaces(d Decorator) []file.Interface {
+	return nil
+}
+
+func (loggingDecorator) Body(m decoratedMethod) file.Block {
+	start := m.local("start")
+	attrs := make([]file.Expr, 0, len(m.Params)+1)
+	for _, arg := range m.args() {
+		attrs = append(attrs, file.Qual(slogImport, "Any").Call(file.Code(strconv.Quote(arg.Name)), file.Code(arg.Name)))
+	}
+	attrs = append(attrs, file.Qual(slogImport, "Duration").Call(
+		file.Code(`"duration"`),
+		file.Qual(timeImport, "Since").Call(file.Code(start)),
+	))
+	ctx, _ := m.context()
+	logAttrs := file.Code(m.recv + ".logger.LogAttrs")
+
+	body := file.Block{
+		file.Define(start, file.Qual(timeImport, "Now").Call()),
+		m.call(),
+	}
+	err := m.err()
+	if err == "" {
+		return append(body,
+			file.Eval(logAttrs.Call(append([]file.Expr{ctx, file.Qual(slogImport, "LevelInfo"), m.qualifiedName()}, attrs...)...)),
+			m.returns(),
+		)
+	}
+
+	level, attrsName := m.local("level"), m.local("attrs")
+	return append(body,
+		file.Define(level, file.Qual(slogImport, "LevelInfo")),
+		file.Define(attrsName, file.Code("[]slog.Attr", slogImport).Lit(attrs...)),
+		file.If(file.Code(err+" != nil"),
+			file.Assign(level, file.Qual(slogImport, "LevelError")),
+			file.Assign(attrsName, file.Code("append").Call(file.Code(attrsName), file.Qual(slogImport, "Any").Call(file.Code(`"error"`), file.Code(err)))),
+		),
+		file.Eval(logAttrs.Call(ctx, file.Code(level), m.qualifiedName(), file.Code(attrsName+"..."))),
+		m.returns(),
+	)
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+// metricsDecorator reports the duration and the error of every call to a
+// recorder declared along with it, which counts and times the calls with
+// the metrics library of the project, e.g. a Prometheus histogram
+// partitioned by method and error.
+type metricsDecorator struct{}
+
+func (metricsDecorator) Fields(d Decorator) []file.Field {
+	return []file.Field{{Name: "recorder", Type: recorderName(d)}}
+}
+
+func (metricsDecorator) Imports() file.Imports {
+	return file.Imports{timeImport}
+}
+
+func (metricsDecorator) Interfaces(d Decorator) []file.Interface {
+	return []file.Interface{{
+		Name: recorderName(d),
+		Methods: []file.Method{{
+			Name: "Record",
+			Params: file.Args{
+				{Name: "method", Type: "string"},
+				{Name: "duration", Type: "time.Duration"},
+				{Name: "err", Type: "error"},
+			},
+		}},
+	}}
+}
+
+func recorderName(d Decorator) string {
+	return d.iface.PascalCase() + "Recorder"
+}
+
+func (metricsDecorator) Body(m decoratedMethod) file.Block {
+	start := m.local("start")
+	err := file.Code("nil")
+	if name := m.err(); name != "" {
+		err = file.Code(name)
+	}
+	return file.Block{
+		file.Define(start, file.Qual(timeImport, "Now").Call()),
+		m.call(),
+		file.Eval(file.Code(m.recv+".recorder.Record").Call(
+			m.qualifiedName(),
+			file.Qual(timeImport, "Since").Call(file.Code(start)),
+			err,
+		)),
+		m.returns(),
+	}
+}
@@ -0,0 +1,176 @@
+package entity_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestNewDecorator(t *testing.T) {
+	iface := entity.NewEntityName("Store", "src/stores", "github.com/eduardoths/microservice")
+	decorated := file.Interface{
+		Name: "Store",
+		Methods: []file.Method{
+			{
+				Name:    "Find",
+				Params:  file.Args{{Type: "context.Context"}, {Type: "string"}, {Name: "tags", Type: "string", Variadic: true}},
+				Results: file.Args{{Type: "int"}, {Type: "error"}},
+			},
+			{
+				Name:   "Flush",
+				Params: file.Args{{Name: "start", Type: "bool"}},
+			},
+		},
+	}
+	imports := file.Imports{{Path: "context"}}
+
+	type testCase struct {
+		it        string
+		kind      string
+		wantParts []string
+	}
+
+	tc := []testCase{
+		{
+			it:   "it should name the unnamed args and forward the variadic ones when logging",
+			kind: "logging",
+			wantParts: []string{
+				"func NewLoggingStore(next Store, logger *slog.Logger) Store {\n",
+				"func (ls *loggingStore) Find(ctx context.Context, arg1 string, tags ...string) (result0 int, err error) {\n",
+				"\tresult0, err = ls.next.Find(ctx, arg1, tags...)\n",
+				"\tattrs := []slog.Attr{slog.Any(\"arg1\", arg1), slog.Any(\"tags\", tags), slog.Duration(\"duration\", time.Since(start))}\n",
+				"\t\tattrs = append(attrs, slog.Any(\"error\", err))\n",
+				"\tls.logger.LogAttrs(ctx, level, \"Store.Find\", attrs...)\n",
+			},
+		},
+		{
+			it:   "it should log the methods without context nor error at the info level",
+			kind: "logging",
+			wantParts: []string{
+				"\tstart2 := time.Now()\n" +
+					"\tls.next.Flush(start)\n" +
+					"\tls.logger.LogAttrs(context.Background(), slog.LevelInfo, \"Store.Flush\", slog.Any(\"start\", start), slog.Duration(\"duration\", time.Since(start2)))\n" +
+					"\t// microcli:end\n",
+			},
+		},
+		{
+			it:   "it should record the calls and declare the recorder",
+			kind: "metrics",
+			wantParts: []string{
+				"type StoreRecorder interface {\n" +
+					"\tRecord(method string, duration time.Duration, err error)\n" +
+					"}\n",
+				"func NewMetricsStore(next Store, recorder StoreRecorder) Store {\n",
+				"\tms.recorder.Record(\"Store.Find\", time.Since(start), err)\n" +
+					"\treturn result0, err\n",
+				"\tms.recorder.Record(\"Store.Flush\", time.Since(start2), nil)\n",
+			},
+		},
+		{
+			it:   "it should start spans with the args of known types as attributes",
+			kind: "tracing",
+			wantParts: []string{
+				"func NewTracingStore(next Store, tracer trace.Tracer) Store {\n",
+				"\tctx, span := ts.tracer.Start(ctx, \"Store.Find\", trace.WithAttributes(attribute.String(\"arg1\", arg1)))\n" +
+					"\tdefer span.End()\n",
+				"\t\tspan.SetStatus(codes.Error, err.Error())\n",
+				"\t_, span := ts.tracer.Start(context.Background(), \"Store.Flush\", trace.WithAttributes(attribute.Bool(\"start\", start)))\n",
+			},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			d, err := entity.NewDecorator(iface, decorated, imports, c.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "src/stores/" + c.kind + "_store.go"; want != d.FilePath() {
+				utils.Error(t, want, d.FilePath())
+			}
+			actual := d.File().String()
+			for _, want := range c.wantParts {
+				if !strings.Contains(actual, want) {
+					utils.Error(t, want, actual)
+				}
+			}
+		})
+	}
+
+	t.Run("it should rename the args shadowing the packages the bodies refer to", func(t *testing.T) {
+		shadowing := file.Interface{
+			Name: "Store",
+			Methods: []file.Method{{
+				Name:   "Save",
+				Params: file.Args{{Name: "context", Type: "string"}, {Name: "time", Type: "int"}, {Name: "codes", Type: "[]string"}},
+			}},
+		}
+		d, err := entity.NewDecorator(iface, shadowing, nil, "tracing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := d.File().String()
+		want := "func (ts *tracingStore) Save(arg0 string, arg1 int, arg2 []string) {\n"
+		if !strings.Contains(actual, want) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should fail on unknown kinds", func(t *testing.T) {
+		_, err := entity.NewDecorator(iface, decorated, imports, "caching")
+		if !errors.Is(err, entity.ErrUnknownDecoratorKind) {
+			utils.Error(t, entity.ErrUnknownDecoratorKind, err)
+		}
+	})
+}
+
+func TestLayerInterface(t *testing.T) {
+	structName := entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice")
+
+	t.Run("it should name the interfaces of the layers", func(t *testing.T) {
+		for layer, want := range map[string]string{
+			entity.REPOSITORY_LAYER: "src/repositories/xpto_struct_name/xpto_struct_name_repository.go",
+			entity.SERVICE_LAYER:    "src/services/xpto_struct_name/xpto_struct_name_service.go",
+		} {
+			iface, err := entity.LayerInterface(structName, "github.com/eduardoths/microservice", layer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if iface.FilePath() != want {
+				utils.Error(t, want, iface.FilePath())
+			}
+		}
+	})
+
+	t.Run("it should fail on unknown layers", func(t *testing.T) {
+		_, err := entity.LayerInterface(structName, "github.com/eduardoths/microservice", "handler")
+		if !errors.Is(err, entity.ErrUnknownLayer) {
+			utils.Error(t, entity.ErrUnknownLayer, err)
+		}
+	})
+}
+
+func TestDecoratorPath(t *testing.T) {
+	iface := entity.NewEntityName("XptoStructNameService", "src/services/xpto_struct_name", "github.com/eduardoths/microservice")
+
+	t.Run("it should name the decorator file after its kind", func(t *testing.T) {
+		path, err := entity.DecoratorPath(iface, "tracing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "src/services/xpto_struct_name/tracing_xpto_struct_name_service.go"; want != path {
+			utils.Error(t, want, path)
+		}
+	})
+
+	t.Run("it should fail on unknown kinds", func(t *testing.T) {
+		_, err := entity.DecoratorPath(iface, "caching")
+		if !errors.Is(err, entity.ErrUnknownDecoratorKind) {
+			utils.Error(t, entity.ErrUnknownDecoratorKind, err)
+		}
+	})
+}
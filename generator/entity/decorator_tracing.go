@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"strconv"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+var (
+	traceImport     = file.Import{Path: "go.opentelemetry.io/otel/trace"}
+	attributeImport = file.Import{Path: "go.opentelemetry.io/otel/attribute"}
+	codesImport     = file.Import{Path: "go.opentelemetry.io/otel/codes"}
+)
+
+// spanAttributes maps the types of the args set as span attributes to the
+// attribute functions of OpenTelemetry. The args of other types are left
+// out of the spans.
+var spanAttributes = map[string]string{
+	"string":    "String",
+	"bool":      "Bool",
+	"int":       "Int",
+	"int64":     "Int64",
+	"float64":   "Float64",
+	"[]string":  "StringSlice",
+	"[]bool":    "BoolSlice",
+	"[]int":     "IntSlice",
+	"[]int64":   "Int64Slice",
+	"[]float64": "Float64Slice",
+	ID_TYPE:     "Stringer",
+}
+
+// tracingDecorator starts an OpenTelemetry span around every call, holding
+// its args as attributes and recording its error.
+type tracingDecorator struct{}
+
+func (tracingDecorator) Fields(d Decorator) []file.Field {
+	return []file.Field{{Name: "tracer", Type: "trace.Tracer"}}
+}
+
+func (tracingDecorator) Imports() file.Imports {
+	return file.Imports{traceImport}
+}
+
+func (tracingDecorator) Interfaces(d Decorator) []file.Interface {
+	return nil
+}
+
+func (tracingDecorator) Body(m decoratedMethod) file.Block {
+	span := m.local("span")
+	ctx, ok := m.context()
+	started := "_, " + span
+	if ok {
+		started = ctx.String() + ", " + span
+	}
+
+	start := []file.Expr{ctx, m.qualifiedName()}
+	attrs := make([]file.Expr, 0, len(m.Params))
+	for _, arg := range m.args() {
+		if fn, ok := spanAttributes[arg.Type]; ok && !arg.Variadic {
+			attrs = append(attrs, file.Qual(attributeImport, fn).Call(file.Code(strconv.Quote(arg.Name)), file.Code(arg.Name)))
+		}
+	}
+	if len(attrs) > 0 {
+		start = append(start, file.Qual(traceImport, "WithAttributes").Call(attrs...))
+	}
+
+	body := file.Block{
+		file.Define(started, file.Code(m.recv+".tracer.Start").Call(start...)),
+		file.Line("defer " + span + ".End()"),
+		m.call(),
+	}
+	if err := m.err(); err != "" {
+		body = append(body, file.If(file.Code(err+" != nil"),
+			file.Eval(file.Code(span+".RecordError").Call(file.Code(err))),
+			file.Eval(file.Code(span+".SetStatus").Call(file.Qual(codesImport, "Error"), file.Code(err+".Error()"))),
+		))
+	}
+	return append(body, m.returns())
+}
@@ -0,0 +1,198 @@
+package entity_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/tests/golden"
+)
+
+const goldenModule = "github.com/eduardoths/microservice"
+
+const goldenSchema = `
+name: XptoStructName
+fields:
+  - name: Name
+    type: string
+  - name: Nickname
+    type: string
+    optional: true
+  - name: Tags
+    type: "[]string"
+  - name: Count
+    type: int
+  - name: Secret
+    type: string
+    tags:
+      json: "-"
+  - name: CreatedAt
+    type: time.Time
+relations:
+  - entity: Customer
+    kind: belongs_to
+`
+
+const goldenSQL = `
+-- the themes of the storefront
+CREATE TABLE themes (
+  id uuid PRIMARY KEY,
+  name text NOT NULL,
+  color varchar(7) DEFAULT '#ffffff', /* hex; with a comment */
+  note text DEFAULT 'a -- b',
+  priority integer,
+  published_at timestamptz
+);
+`
+
+const goldenJSON = `{
+	"id": 1,
+	"customerName": "xpto",
+	"total": 10.5,
+	"created_at": "2024-01-02T03:04:05Z",
+	"shipping": {"street": "a", "geo": {"lat": 1.5}},
+	"items": [{"sku": "a", "qty": 1}, {"sku": "b"}],
+	"note": null
+}`
+
+func TestGolden(t *testing.T) {
+	e, err := entity.NewEntity(entity.EntitySchema{
+		Name:   "XptoStructName",
+		ID:     entity.SchemaID{Name: "ID"},
+		Fields: []entity.SchemaField{{Name: "Name", Type: "string"}},
+	}, "src/structs", goldenModule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := entity.NewRepository(e.Name(), goldenModule)
+	service := entity.NewService(e.Name(), goldenModule)
+
+	t.Run("it should match the golden files", func(t *testing.T) {
+		golden.Assert(t, "entity", e.File().String())
+		golden.Assert(t, "repository", repo.File().String())
+		golden.Assert(t, "service", service.File().String())
+		golden.Assert(t, "service_test", service.TestFile().String())
+	})
+
+	for _, framework := range entity.HandlerFrameworks() {
+		name := "handler_" + strings.ReplaceAll(framework, "/", "")
+		handler, err := entity.NewHandlerWithFramework(e.Name(), goldenModule, framework)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("it should match the "+framework+" handler golden files", func(t *testing.T) {
+			golden.Assert(t, name, handler.File().String())
+			golden.Assert(t, name+"_test", handler.TestFile().String())
+		})
+
+		t.Run("it should compile the golden files of the "+framework+" layers", func(t *testing.T) {
+			golden.Compile(t, goldenModule, map[string]string{
+				e.FilePath():           "entity",
+				repo.FilePath():        "repository",
+				service.FilePath():     "service",
+				service.TestFilePath(): "service_test",
+				handler.FilePath():     name,
+				handler.TestFilePath(): name + "_test",
+			})
+		})
+	}
+
+	schema, err := entity.ParseEntitySchema([]byte(goldenSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromSchema, err := entity.NewEntityWithTags(schema, "src/structs", goldenModule, entity.TagNaming{entity.JSON_TAG: entity.NAMING_SNAKE, "db": entity.NAMING_SNAKE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := entity.NewProto(fromSchema.Name(), goldenModule, fromSchema.Struct, entity.DefaultProtoTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := entity.NewOpenAPI("microservice", goldenModule, []entity.EntityName{fromSchema.Name()}, []file.Struct{fromSchema.Struct}, entity.DefaultSchemaTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("it should match the golden files of the schema entity, its proto and its OpenAPI document", func(t *testing.T) {
+		golden.Assert(t, "entity_schema", fromSchema.File().String())
+		golden.Assert(t, "proto", p.ProtoFile().String())
+		golden.Assert(t, "grpc_server", p.File().String())
+		golden.Assert(t, "openapi", spec.Document().String())
+	})
+
+	t.Run("it should compile the gRPC adapter of the schema entity", func(t *testing.T) {
+		golden.Compile(t, goldenModule, map[string]string{
+			fromSchema.FilePath(): "entity_schema",
+			repo.FilePath():       "repository",
+			service.FilePath():    "service",
+			p.FilePath():          "grpc_server",
+		})
+	})
+
+	sqlSchemas, _, err := entity.ParseSQLSchemas(goldenSQL, entity.DIALECT_POSTGRES, entity.NULLABLE_SQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromSQL, err := entity.NewEntity(sqlSchemas[0], "src/structs", goldenModule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := entity.NewJSONStruct(entity.NewEntityName("ThirdPartyOrder", "src/structs", goldenModule), [][]byte{[]byte(goldenJSON)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("it should match the golden files of the SQL entity and of the JSON struct", func(t *testing.T) {
+		golden.Assert(t, "entity_sql", fromSQL.File().String())
+		golden.Assert(t, "json_struct", fromJSON.File().String())
+	})
+
+	t.Run("it should compile the SQL entity and the JSON struct", func(t *testing.T) {
+		golden.Compile(t, goldenModule, map[string]string{
+			fromSQL.FilePath():  "entity_sql",
+			fromJSON.FilePath(): "json_struct",
+		})
+	})
+
+	root := t.TempDir()
+	repoPath := filepath.Join(root, repo.FilePath())
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repoPath, []byte(repo.File().String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	iface, err := entity.LayerInterface(e.Name(), goldenModule, entity.REPOSITORY_LAYER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decorated, imports, err := entity.LoadInterface(root, iface)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kind := range entity.DecoratorKinds() {
+		name := "decorator_" + kind
+		d, err := entity.NewDecorator(iface, decorated, imports, kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("it should match the "+kind+" decorator golden file", func(t *testing.T) {
+			golden.Assert(t, name, d.File().String())
+		})
+
+		t.Run("it should compile the "+kind+" decorator of the repository", func(t *testing.T) {
+			golden.Compile(t, goldenModule, map[string]string{
+				e.FilePath():    "entity",
+				repo.FilePath(): "repository",
+				d.FilePath():    name,
+			})
+		})
+	}
+}
@@ -0,0 +1,463 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/utils"
+)
+
+type Handler struct {
+	handlerName EntityName
+	service     Service
+	renderer    handlerRenderer
+	handler     file.Struct
+	helpers     file.Struct
+
+	Imports file.Imports
+	Vars    []file.Var
+}
+
+type endpoint struct {
+	name   string
+	verb   string
+	path   string
+	status string
+	code   int
+}
+
+// endpoints maps each repository method to the route that exposes it.
+var endpoints = map[string]endpoint{
+	"GetAll": {name: "list", verb: "GET", status: "http.StatusOK", code: 200},
+	"Get":    {name: "get", verb: "GET", path: "/{id}", status: "http.StatusOK", code: 200},
+	"Create": {name: "create", verb: "POST", status: "http.StatusCreated", code: 201},
+	"Update": {name: "update", verb: "PUT", path: "/{id}", status: "http.StatusOK", code: 200},
+	"Delete": {name: "delete", verb: "DELETE", path: "/{id}", status: "http.StatusNoContent", code: 204},
+}
+
+func (e endpoint) httpMethod() string {
+	return "http.Method" + strings.Title(strings.ToLower(e.verb))
+}
+
+type route struct {
+	endpoint
+	method file.Method
+	path   string
+}
+
+func NewHandler(structName EntityName, basePkg string) Handler {
+	handler, _ := NewHandlerWithFramework(structName, basePkg, DEFAULT_HANDLER_FRAMEWORK)
+	return handler
+}
+
+func NewHandlerWithFramework(structName EntityName, basePkg string, framework string) (Handler, error) {
+	renderer, ok := handlerRenderers[framework]
+	if !ok {
+		return Handler{}, fmt.Errorf("%w: %q, expected one of %s",
+			ErrUnknownFramework, framework, strings.Join(HandlerFrameworks(), ", "))
+	}
+
+	handler := Handler{
+		handlerName: NewEntityName(
+			structName.PascalCase()+"Handler",
+			utils.MergePaths(HANDLERS_PATH, structName.SnakeCase()),
+			basePkg,
+		),
+		service:  NewService(structName, basePkg),
+		renderer: renderer,
+	}
+	handler.build()
+
+	return handler, nil
+}
+
+func (h *Handler) build() {
+	h.buildImports()
+	h.buildHandler()
+	h.buildHelpers()
+	h.Vars = h.renderer.Vars(*h)
+}
+
+func (h *Handler) File() file.File {
+	return file.File{
+		Package: h.handlerName.ImportName(),
+		Imports: h.Imports,
+		Vars:    h.Vars,
+		Structs: []file.Struct{h.handler, h.helpers},
+	}
+}
+
+func (h Handler) FilePath() string {
+	return h.handlerName.FilePath()
+}
+
+func (h Handler) Framework() string {
+	return h.renderer.Framework()
+}
+
+func (h Handler) RoutePrefix() string {
+	return "/" + h.service.repository.structName.Plural().KebabCase()
+}
+
+func (h Handler) name() string {
+	return h.handlerName.PascalCase()
+}
+
+func (h Handler) alias() string {
+	return h.handlerName.Alias()
+}
+
+func (h *Handler) buildImports() {
+	h.Imports = file.Imports{
+		{Path: ERRORS_PKG},
+		{Path: "net/http"},
+		h.service.serviceName.FileImport(),
+	}
+	h.Imports = append(h.Imports, h.renderer.Imports()...)
+	for _, r := range h.routes() {
+		for _, param := range r.method.Params {
+			switch param.Type {
+			case ID_TYPE:
+				h.Imports = append(h.Imports, file.Import{Path: ID_PKG})
+			case h.service.repository.structName.Type():
+				h.Imports = append(h.Imports, h.service.repository.structName.FileImport())
+			}
+		}
+	}
+}
+
+func (h Handler) routes() []route {
+	methods := h.service.Interface.MethodSet()
+	routes := make([]route, 0, len(methods))
+	for _, m := range methods {
+		if e, ok := endpoints[m.Name]; ok {
+			routes = append(routes, route{
+				endpoint: e,
+				method:   m,
+				path:     h.RoutePrefix() + e.path,
+			})
+		}
+	}
+	return routes
+}
+
+func (h *Handler) buildHandler() {
+	h.handler = file.Struct{
+		Name: h.name(),
+		Fields: append([]file.Field{
+			{Name: "service", Type: h.service.serviceName.Type()},
+		}, h.renderer.Fields()...),
+	}
+
+	h.handler.Implementations = append(h.handler.Implementations, h.renderer.Register(*h, h.routes())...)
+
+	params, results := h.renderer.Signature()
+	for _, r := range h.routes() {
+		h.handler.Implementations = append(h.handler.Implementations, file.Implementation{
+			StructAlias: h.alias(),
+			StructName:  "*" + h.name(),
+			Func: file.Method{
+				Name:    r.name,
+				Params:  params,
+				Results: results,
+			},
+			CodeLines:  h.endpointBody(r),
+			UserRegion: true,
+		})
+	}
+}
+
+func (h Handler) constructor(codeLines []string) file.Implementation {
+	return file.Implementation{
+		Func: file.Method{
+			Name:    "New" + h.name(),
+			Params:  file.Args{{Name: "service", Type: h.service.serviceName.Type()}},
+			Results: file.Args{{Type: "*" + h.name()}},
+		},
+		CodeLines: codeLines,
+	}
+}
+
+func (h Handler) plainConstructor() file.Implementation {
+	return h.constructor([]string{"return &" + h.name() + "{service: service}"})
+}
+
+func (h Handler) fail(status string, err string) []string {
+	if h.renderer.ReturnsError() {
+		return []string{"\t" + h.renderer.Fail(status, err)}
+	}
+	return []string{"\t" + h.renderer.Fail(status, err), "\treturn"}
+}
+
+func (h Handler) endpointBody(r route) []string {
+	lines := make([]string, 0)
+	args := make([]string, 0, len(r.method.Params))
+
+	for _, param := range r.method.Params {
+		switch {
+		case param.Type == CONTEXT_TYPE:
+			args = append(args, h.renderer.Context())
+		case param.Type == ID_TYPE:
+			lines = append(lines, param.Name+", err := "+ID_PARSE_FUNC+"("+h.renderer.PathParam(param.Name)+")")
+			lines = append(lines, "if err != nil {")
+			lines = append(lines, h.fail("http.StatusBadRequest", "err")...)
+			lines = append(lines, "}")
+			args = append(args, param.Name)
+		default:
+			lines = append(lines, "var "+param.Name+" "+param.Type)
+			lines = append(lines, "if err := "+h.renderer.Decode("&"+param.Name)+"; err != nil {")
+			lines = append(lines, h.fail("http.StatusBadRequest", "err")...)
+			lines = append(lines, "}")
+			lines = append(lines, "if err := validate("+param.Name+"); err != nil {")
+			lines = append(lines, h.fail("http.StatusBadRequest", "err")...)
+			lines = append(lines, "}")
+			args = append(args, param.Name)
+		}
+	}
+
+	call := h.alias() + ".service." + r.method.Name + "(" + strings.Join(args, ", ") + ")"
+	result := responseResult(r.method)
+	if result == "" {
+		lines = append(lines, "if err := "+call+"; err != nil {")
+		lines = append(lines, h.fail("statusFor(err)", "err")...)
+		lines = append(lines, "}")
+		return append(lines, h.renderer.NoContent(r.status))
+	}
+	lines = append(lines, result+", err := "+call, "if err != nil {")
+	lines = append(lines, h.fail("statusFor(err)", "err")...)
+	lines = append(lines, "}")
+	return append(lines, h.renderer.Respond(r.status, result))
+}
+
+func responseResult(m file.Method) string {
+	for _, result := range m.Results {
+		if result.Type != "error" {
+			return result.Name
+		}
+	}
+	return ""
+}
+
+func (h *Handler) buildHelpers() {
+	h.helpers = file.Struct{
+		Name:   "errorResponse",
+		Fields: []file.Field{{Name: "Error", Type: "string", Tag: file.Tag{{Key: JSON_TAG, Value: "error"}}.String()}},
+	}
+	h.helpers.Implementations = append(h.helpers.Implementations, h.renderer.Helpers()...)
+	h.helpers.Implementations = append(h.helpers.Implementations,
+		file.Implementation{
+			Func: file.Method{
+				Name:    "statusFor",
+				Params:  file.Args{{Name: "err", Type: "error"}},
+				Results: file.Args{{Type: "int"}},
+			},
+			CodeLines: []string{
+				"if errors.Is(err, " + h.service.serviceName.ImportName() + ".ErrNotFound) {",
+				"\treturn http.StatusNotFound",
+				"}",
+				"return http.StatusInternalServerError",
+			},
+		},
+		file.Implementation{
+			Func: file.Method{
+				Name:    "validate",
+				Params:  file.Args{{Name: "v", Type: "any"}},
+				Results: file.Args{{Type: "error"}},
+			},
+			CodeLines: []string{
+				"if validator, ok := v.(interface{ Validate() error }); ok {",
+				"\treturn validator.Validate()",
+				"}",
+				"return nil",
+			},
+		},
+	)
+}
+
+func (h Handler) TestFilePath() string {
+	return testFilePath(h.FilePath())
+}
+
+func (h Handler) TestFile() file.File {
+	fake := h.fakeService()
+	imports := file.Imports{
+		{Path: "net/http"},
+		{Path: "net/http/httptest"},
+		{Path: "strings"},
+		{Path: "testing"},
+		{Path: ERRORS_PKG},
+		{Path: ID_PKG},
+		h.service.serviceName.FileImport(),
+	}
+	imports = append(imports, h.renderer.TestImports()...)
+	for _, imethod := range h.service.repository.internalMethods() {
+		imports = append(imports, imethod.imports...)
+	}
+
+	return file.File{
+		Package: h.handlerName.ImportName(),
+		Imports: imports,
+		Funcs: []file.Implementation{
+			{
+				Func: file.Method{
+					Name:   "Test" + h.handlerName.PascalCase(),
+					Params: file.Args{{Name: "t", Type: "*testing.T"}},
+				},
+				CodeLines: h.testBody(fake.Name),
+			},
+		},
+		Structs: []file.Struct{fake},
+	}
+}
+
+func (h Handler) fakeService() file.Struct {
+	return fake(NewEntityName("fake"+h.service.serviceName.PascalCase(), "", ""), h.service.Interface.MethodSet())
+}
+
+type handlerTestCase struct {
+	it     string
+	method string
+	target string
+	body   string
+	err    string
+	want   string
+}
+
+func (h Handler) testCases() []handlerTestCase {
+	plural := strings.ReplaceAll(h.service.repository.structName.Plural().SnakeCase(), "_", " ")
+	singular := strings.ReplaceAll(h.service.repository.structName.SnakeCase(), "_", " ")
+
+	cases := make([]handlerTestCase, 0)
+	for _, r := range h.routes() {
+		m, e := r.method, r.endpoint
+		target := `"` + h.RoutePrefix() + `"`
+		hasID, hasBody := false, false
+		for _, param := range m.Params {
+			switch param.Type {
+			case CONTEXT_TYPE:
+			case ID_TYPE:
+				hasID = true
+			default:
+				hasBody = true
+			}
+		}
+		if hasID {
+			target = `"` + h.RoutePrefix() + `/" + id`
+		}
+		body := ""
+		if hasBody {
+			body = `"{}"`
+		}
+
+		subject := singular
+		if m.Name == "GetAll" {
+			subject = plural
+		}
+		cases = append(cases, handlerTestCase{
+			it:     "should " + e.name + " " + subject,
+			method: e.httpMethod(),
+			target: target,
+			body:   body,
+			want:   e.status,
+		})
+		if m.Name == "GetAll" {
+			cases = append(cases, handlerTestCase{
+				it:     "should return internal server error when " + e.name + " fails",
+				method: e.httpMethod(),
+				target: target,
+				err:    `errors.New("unexpected")`,
+				want:   "http.StatusInternalServerError",
+			})
+		}
+		if hasID {
+			cases = append(cases,
+				handlerTestCase{
+					it:     "should return bad request when " + e.name + " receives an invalid id",
+					method: e.httpMethod(),
+					target: `"` + h.RoutePrefix() + `/invalid"`,
+					body:   body,
+					want:   "http.StatusBadRequest",
+				},
+				handlerTestCase{
+					it:     "should return not found when " + e.name + " does not find the " + singular,
+					method: e.httpMethod(),
+					target: target,
+					body:   body,
+					err:    h.service.serviceName.ImportName() + ".ErrNotFound",
+					want:   "http.StatusNotFound",
+				},
+			)
+		}
+		if hasBody {
+			cases = append(cases, handlerTestCase{
+				it:     "should return bad request when " + e.name + " receives an invalid body",
+				method: e.httpMethod(),
+				target: target,
+				body:   `"{"`,
+				want:   "http.StatusBadRequest",
+			})
+		}
+	}
+	return cases
+}
+
+func (h Handler) testBody(fakeName string) []string {
+	lines := []string{
+		"id := uuid.NewString()",
+		"",
+		"type testCase struct {",
+		"\tit     string",
+		"\tmethod string",
+		"\ttarget string",
+		"\tbody   string",
+		"\terr    error",
+		"\twant   int",
+		"}",
+		"",
+		"tc := []testCase{",
+	}
+	for _, c := range h.testCases() {
+		lines = append(lines,
+			"\t{",
+			"\t\tit:     "+strconv.Quote(c.it)+",",
+			"\t\tmethod: "+c.method+",",
+			"\t\ttarget: "+c.target+",",
+		)
+		if c.body != "" {
+			lines = append(lines, "\t\tbody:   "+c.body+",")
+		}
+		if c.err != "" {
+			lines = append(lines, "\t\terr:    "+c.err+",")
+		}
+		lines = append(lines,
+			"\t\twant:   "+c.want+",",
+			"\t},",
+		)
+	}
+	lines = append(lines,
+		"}",
+		"",
+		"for _, c := range tc {",
+		"\tt.Run(c.it, func(t *testing.T) {",
+		"\t\treq := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))",
+		"\t\t"+`req.Header.Set("Content-Type", "application/json")`,
+	)
+	for _, line := range h.renderer.TestServe(h.constructorCall(fakeName)) {
+		lines = append(lines, "\t\t"+line)
+	}
+	lines = append(lines,
+		"",
+		"\t\tif code != c.want {",
+		"\t\t\t"+`t.Errorf("%s %s returned %d, want %d", c.method, c.target, code, c.want)`,
+		"\t\t}",
+		"\t})",
+		"}",
+	)
+	return lines
+}
+
+func (h Handler) constructorCall(fakeName string) string {
+	return "New" + h.name() + "(" + fakeName + "{err: c.err})"
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+const CHI_PKG = "github.com/go-chi/chi/v5"
+
+// chiRenderer reuses the net/http request and response handling, as chi
+// handlers are plain http.HandlerFuncs.
+type chiRenderer struct {
+	netHTTPRenderer
+}
+
+func (chiRenderer) Framework() string { return "chi" }
+
+func (chiRenderer) Imports() file.Imports {
+	return file.Imports{{Path: "encoding/json"}, {Path: CHI_PKG}}
+}
+
+func (chiRenderer) Fields() []file.Field { return nil }
+
+func (chiRenderer) Vars(Handler) []file.Var { return nil }
+
+func (chiRenderer) Register(h Handler, routes []route) []file.Implementation {
+	codeLines := make([]string, 0, len(routes))
+	for _, r := range routes {
+		verb := strings.Title(strings.ToLower(r.verb))
+		codeLines = append(codeLines, "r."+verb+`("`+r.path+`", `+h.alias()+"."+r.name+")")
+	}
+
+	return []file.Implementation{
+		h.plainConstructor(),
+		{
+			StructAlias: h.alias(),
+			StructName:  "*" + h.name(),
+			Func: file.Method{
+				Name:   "Routes",
+				Params: file.Args{{Name: "r", Type: "chi.Router"}},
+			},
+			CodeLines: codeLines,
+		},
+	}
+}
+
+func (chiRenderer) PathParam(name string) string {
+	return `chi.URLParam(r, "` + name + `")`
+}
+
+func (chiRenderer) TestImports() file.Imports {
+	return file.Imports{{Path: CHI_PKG}}
+}
+
+func (chiRenderer) TestServe(constructorCall string) []string {
+	return []string{
+		"router := chi.NewRouter()",
+		constructorCall + ".Routes(router)",
+		"rec := httptest.NewRecorder()",
+		"router.ServeHTTP(rec, req)",
+		"code := rec.Code",
+	}
+}
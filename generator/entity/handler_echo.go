@@ -0,0 +1,81 @@
+package entity
+
+import "github.com/eduardoths/micro-cli/generator/file"
+
+const ECHO_PKG = "github.com/labstack/echo/v4"
+
+type echoRenderer struct{}
+
+func (echoRenderer) Framework() string { return "echo" }
+
+func (echoRenderer) Imports() file.Imports {
+	return file.Imports{{Path: ECHO_PKG}}
+}
+
+func (echoRenderer) Fields() []file.Field { return nil }
+
+func (echoRenderer) Vars(Handler) []file.Var { return nil }
+
+func (echoRenderer) Register(h Handler, routes []route) []file.Implementation {
+	codeLines := make([]string, 0, len(routes))
+	for _, r := range routes {
+		codeLines = append(codeLines, "g."+r.verb+`("`+colonPath(r.path)+`", `+h.alias()+"."+r.name+")")
+	}
+
+	return []file.Implementation{
+		h.plainConstructor(),
+		{
+			StructAlias: h.alias(),
+			StructName:  "*" + h.name(),
+			Func: file.Method{
+				Name:   "Register",
+				Params: file.Args{{Name: "g", Type: "*echo.Group"}},
+			},
+			CodeLines: codeLines,
+		},
+	}
+}
+
+func (echoRenderer) Helpers() []file.Implementation { return nil }
+
+func (echoRenderer) Signature() (file.Args, file.Args) {
+	return file.Args{{Name: "c", Type: "echo.Context"}}, file.Args{{Type: "error"}}
+}
+
+func (echoRenderer) ReturnsError() bool { return true }
+
+func (echoRenderer) Context() string { return "c.Request().Context()" }
+
+func (echoRenderer) PathParam(name string) string {
+	return `c.Param("` + name + `")`
+}
+
+func (echoRenderer) Decode(target string) string {
+	return "c.Bind(" + target + ")"
+}
+
+func (echoRenderer) Respond(status string, value string) string {
+	return "return c.JSON(" + status + ", " + value + ")"
+}
+
+func (echoRenderer) NoContent(status string) string {
+	return "return c.NoContent(" + status + ")"
+}
+
+func (echoRenderer) Fail(status string, err string) string {
+	return "return c.JSON(" + status + ", errorResponse{Error: " + err + ".Error()})"
+}
+
+func (echoRenderer) TestImports() file.Imports {
+	return file.Imports{{Path: ECHO_PKG}}
+}
+
+func (echoRenderer) TestServe(constructorCall string) []string {
+	return []string{
+		"e := echo.New()",
+		constructorCall + `.Register(e.Group(""))`,
+		"rec := httptest.NewRecorder()",
+		"e.ServeHTTP(rec, req)",
+		"code := rec.Code",
+	}
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+const FIBER_PKG = "github.com/gofiber/fiber/v2"
+
+type fiberRenderer struct{}
+
+func (fiberRenderer) Framework() string { return "fiber" }
+
+func (fiberRenderer) Imports() file.Imports {
+	return file.Imports{{Path: FIBER_PKG}}
+}
+
+func (fiberRenderer) Fields() []file.Field { return nil }
+
+func (fiberRenderer) Vars(Handler) []file.Var { return nil }
+
+func (fiberRenderer) Register(h Handler, routes []route) []file.Implementation {
+	codeLines := make([]string, 0, len(routes))
+	for _, r := range routes {
+		verb := strings.Title(strings.ToLower(r.verb))
+		codeLines = append(codeLines, "r."+verb+`("`+colonPath(r.path)+`", `+h.alias()+"."+r.name+")")
+	}
+
+	return []file.Implementation{
+		h.plainConstructor(),
+		{
+			StructAlias: h.alias(),
+			StructName:  "*" + h.name(),
+			Func: file.Method{
+				Name:   "Register",
+				Params: file.Args{{Name: "r", Type: "fiber.Router"}},
+			},
+			CodeLines: codeLines,
+		},
+	}
+}
+
+func (fiberRenderer) Helpers() []file.Implementation { return nil }
+
+func (fiberRenderer) Signature() (file.Args, file.Args) {
+	return file.Args{{Name: "c", Type: "*fiber.Ctx"}}, file.Args{{Type: "error"}}
+}
+
+func (fiberRenderer) ReturnsError() bool { return true }
+
+func (fiberRenderer) Context() string { return "c.UserContext()" }
+
+func (fiberRenderer) PathParam(name string) string {
+	return `c.Params("` + name + `")`
+}
+
+func (fiberRenderer) Decode(target string) string {
+	return "c.BodyParser(" + target + ")"
+}
+
+func (fiberRenderer) Respond(status string, value string) string {
+	return "return c.Status(" + status + ").JSON(" + value + ")"
+}
+
+func (fiberRenderer) NoContent(status string) string {
+	return "return c.SendStatus(" + status + ")"
+}
+
+func (fiberRenderer) Fail(status string, err string) string {
+	return "return c.Status(" + status + ").JSON(errorResponse{Error: " + err + ".Error()})"
+}
+
+func (fiberRenderer) TestImports() file.Imports {
+	return file.Imports{{Path: FIBER_PKG}}
+}
+
+func (fiberRenderer) TestServe(constructorCall string) []string {
+	return []string{
+		"app := fiber.New()",
+		constructorCall + ".Register(app)",
+		"res, err := app.Test(req)",
+		"if err != nil {",
+		"\tt.Fatal(err)",
+		"}",
+		"code := res.StatusCode",
+	}
+}
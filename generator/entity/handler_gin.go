@@ -0,0 +1,82 @@
+package entity
+
+import "github.com/eduardoths/micro-cli/generator/file"
+
+const GIN_PKG = "github.com/gin-gonic/gin"
+
+type ginRenderer struct{}
+
+func (ginRenderer) Framework() string { return "gin" }
+
+func (ginRenderer) Imports() file.Imports {
+	return file.Imports{{Path: GIN_PKG}}
+}
+
+func (ginRenderer) Fields() []file.Field { return nil }
+
+func (ginRenderer) Vars(Handler) []file.Var { return nil }
+
+func (ginRenderer) Register(h Handler, routes []route) []file.Implementation {
+	codeLines := make([]string, 0, len(routes))
+	for _, r := range routes {
+		codeLines = append(codeLines, "r."+r.verb+`("`+colonPath(r.path)+`", `+h.alias()+"."+r.name+")")
+	}
+
+	return []file.Implementation{
+		h.plainConstructor(),
+		{
+			StructAlias: h.alias(),
+			StructName:  "*" + h.name(),
+			Func: file.Method{
+				Name:   "Register",
+				Params: file.Args{{Name: "r", Type: "gin.IRouter"}},
+			},
+			CodeLines: codeLines,
+		},
+	}
+}
+
+func (ginRenderer) Helpers() []file.Implementation { return nil }
+
+func (ginRenderer) Signature() (file.Args, file.Args) {
+	return file.Args{{Name: "c", Type: "*gin.Context"}}, nil
+}
+
+func (ginRenderer) ReturnsError() bool { return false }
+
+func (ginRenderer) Context() string { return "c.Request.Context()" }
+
+func (ginRenderer) PathParam(name string) string {
+	return `c.Param("` + name + `")`
+}
+
+func (ginRenderer) Decode(target string) string {
+	return "c.ShouldBindJSON(" + target + ")"
+}
+
+func (ginRenderer) Respond(status string, value string) string {
+	return "c.JSON(" + status + ", " + value + ")"
+}
+
+func (ginRenderer) NoContent(status string) string {
+	return "c.Status(" + status + ")"
+}
+
+func (ginRenderer) Fail(status string, err string) string {
+	return "c.JSON(" + status + ", errorResponse{Error: " + err + ".Error()})"
+}
+
+func (ginRenderer) TestImports() file.Imports {
+	return file.Imports{{Path: GIN_PKG}}
+}
+
+func (ginRenderer) TestServe(constructorCall string) []string {
+	return []string{
+		"gin.SetMode(gin.TestMode)",
+		"router := gin.New()",
+		constructorCall + ".Register(router)",
+		"rec := httptest.NewRecorder()",
+		"router.ServeHTTP(rec, req)",
+		"code := rec.Code",
+	}
+}
@@ -0,0 +1,125 @@
+package entity
+
+import "github.com/eduardoths/micro-cli/generator/file"
+
+type netHTTPRenderer struct{}
+
+func (netHTTPRenderer) Framework() string { return DEFAULT_HANDLER_FRAMEWORK }
+
+func (netHTTPRenderer) Imports() file.Imports {
+	return file.Imports{{Path: "encoding/json"}}
+}
+
+func (netHTTPRenderer) Fields() []file.Field {
+	return []file.Field{{Name: "mux", Type: "*http.ServeMux"}}
+}
+
+func (netHTTPRenderer) Vars(h Handler) []file.Var {
+	return []file.Var{
+		{Name: "_", Type: "http.Handler", Value: "(*" + h.name() + ")(nil)"},
+	}
+}
+
+func (netHTTPRenderer) Register(h Handler, routes []route) []file.Implementation {
+	alias := h.alias()
+	codeLines := []string{
+		alias + " := &" + h.name() + "{",
+		"\tservice: service,",
+		"\tmux:     http.NewServeMux(),",
+		"}",
+	}
+	for _, r := range routes {
+		codeLines = append(codeLines, alias+`.mux.HandleFunc("`+r.verb+" "+r.path+`", `+alias+"."+r.name+")")
+	}
+	codeLines = append(codeLines, "return "+alias)
+
+	return []file.Implementation{
+		h.constructor(codeLines),
+		{
+			StructAlias: alias,
+			StructName:  "*" + h.name(),
+			Func: file.Method{
+				Name: "ServeHTTP",
+				Params: file.Args{
+					{Name: "w", Type: "http.ResponseWriter"},
+					{Name: "r", Type: "*http.Request"},
+				},
+			},
+			CodeLines: []string{alias + ".mux.ServeHTTP(w, r)"},
+		},
+	}
+}
+
+func (netHTTPRenderer) Helpers() []file.Implementation {
+	return []file.Implementation{
+		{
+			Func: file.Method{
+				Name: "writeJSON",
+				Params: file.Args{
+					{Name: "w", Type: "http.ResponseWriter"},
+					{Name: "status", Type: "int"},
+					{Name: "v", Type: "any"},
+				},
+			},
+			CodeLines: []string{
+				`w.Header().Set("Content-Type", "application/json")`,
+				"w.WriteHeader(status)",
+				"_ = json.NewEncoder(w).Encode(v)",
+			},
+		},
+		{
+			Func: file.Method{
+				Name: "writeError",
+				Params: file.Args{
+					{Name: "w", Type: "http.ResponseWriter"},
+					{Name: "status", Type: "int"},
+					{Name: "err", Type: "error"},
+				},
+			},
+			CodeLines: []string{
+				"writeJSON(w, status, errorResponse{Error: err.Error()})",
+			},
+		},
+	}
+}
+
+func (netHTTPRenderer) Signature() (file.Args, file.Args) {
+	return file.Args{
+		{Name: "w", Type: "http.ResponseWriter"},
+		{Name: "r", Type: "*http.Request"},
+	}, nil
+}
+
+func (netHTTPRenderer) ReturnsError() bool { return false }
+
+func (netHTTPRenderer) Context() string { return "r.Context()" }
+
+func (netHTTPRenderer) PathParam(name string) string {
+	return `r.PathValue("` + name + `")`
+}
+
+func (netHTTPRenderer) Decode(target string) string {
+	return "json.NewDecoder(r.Body).Decode(" + target + ")"
+}
+
+func (netHTTPRenderer) Respond(status string, value string) string {
+	return "writeJSON(w, " + status + ", " + value + ")"
+}
+
+func (netHTTPRenderer) NoContent(status string) string {
+	return "w.WriteHeader(" + status + ")"
+}
+
+func (netHTTPRenderer) Fail(status string, err string) string {
+	return "writeError(w, " + status + ", " + err + ")"
+}
+
+func (netHTTPRenderer) TestImports() file.Imports { return nil }
+
+func (netHTTPRenderer) TestServe(constructorCall string) []string {
+	return []string{
+		"rec := httptest.NewRecorder()",
+		constructorCall + ".ServeHTTP(rec, req)",
+		"code := rec.Code",
+	}
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+const DEFAULT_HANDLER_FRAMEWORK = "net/http"
+
+var ErrUnknownFramework = errors.New("unknown handler framework")
+
+// handlerRenderer renders the framework specific parts of a Handler. The
+// endpoint bodies are assembled by the Handler from these primitives, so a
+// renderer only needs to know how its framework reads requests and writes
+// responses.
+type handlerRenderer interface {
+	Framework() string
+	Imports() file.Imports
+	Fields() []file.Field
+	Vars(h Handler) []file.Var
+	Register(h Handler, routes []route) []file.Implementation
+	Helpers() []file.Implementation
+
+	Signature() (params file.Args, results file.Args)
+	ReturnsError() bool
+	Context() string
+	PathParam(name string) string
+	Decode(target string) string
+	Respond(status string, value string) string
+	NoContent(status string) string
+	Fail(status string, err string) string
+
+	TestImports() file.Imports
+	TestServe(constructorCall string) []string
+}
+
+// handlerRenderers are the supported frameworks. The set is closed: a new
+// framework is added here along with its renderer, as renderers build on the
+// unexported routes of the Handler.
+var handlerRenderers = map[string]handlerRenderer{
+	DEFAULT_HANDLER_FRAMEWORK: netHTTPRenderer{},
+	"chi":                     chiRenderer{},
+	"gin":                     ginRenderer{},
+	"echo":                    echoRenderer{},
+	"fiber":                   fiberRenderer{},
+}
+
+func HandlerFrameworks() []string {
+	frameworks := make([]string, 0, len(handlerRenderers))
+	for framework := range handlerRenderers {
+		frameworks = append(frameworks, framework)
+	}
+	sort.Strings(frameworks)
+	return frameworks
+}
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// colonPath converts "/items/{id}" into "/items/:id".
+func colonPath(path string) string {
+	return pathParam.ReplaceAllString(path, ":${1}")
+}
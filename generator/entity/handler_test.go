@@ -0,0 +1,222 @@
+package entity_test
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Run("it should return the handler file paths", func(t *testing.T) {
+		handler := entity.NewHandler(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		if want := "src/handlers/xpto_struct_name/xpto_struct_name_handler.go"; want != handler.FilePath() {
+			utils.Error(t, want, handler.FilePath())
+		}
+		if want := "src/handlers/xpto_struct_name/xpto_struct_name_handler_test.go"; want != handler.TestFilePath() {
+			utils.Error(t, want, handler.TestFilePath())
+		}
+	})
+
+	t.Run("it should use the plural kebab-case entity name as route prefix", func(t *testing.T) {
+		handler := entity.NewHandler(
+			entity.NewEntityName("ProductCategory", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		if want := "/product-categories"; want != handler.RoutePrefix() {
+			utils.Error(t, want, handler.RoutePrefix())
+		}
+	})
+
+	t.Run("it should register every CRUD route", func(t *testing.T) {
+		handler := entity.NewHandler(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		actual := handler.File().String()
+		wantParts := []string{
+			"var _ http.Handler = (*XptoStructNameHandler)(nil)\n",
+			"func NewXptoStructNameHandler(service xptostructname.XptoStructNameService) *XptoStructNameHandler {\n",
+			"\txsnh.mux.HandleFunc(\"GET /xpto-struct-names\", xsnh.list)\n",
+			"\txsnh.mux.HandleFunc(\"GET /xpto-struct-names/{id}\", xsnh.get)\n",
+			"\txsnh.mux.HandleFunc(\"POST /xpto-struct-names\", xsnh.create)\n",
+			"\txsnh.mux.HandleFunc(\"PUT /xpto-struct-names/{id}\", xsnh.update)\n",
+			"\txsnh.mux.HandleFunc(\"DELETE /xpto-struct-names/{id}\", xsnh.delete)\n",
+			"func (xsnh *XptoStructNameHandler) delete(w http.ResponseWriter, r *http.Request) {\n" +
+				"\t// microcli:begin XptoStructNameHandler.delete\n" +
+				"\tid, err := uuid.Parse(r.PathValue(\"id\"))\n" +
+				"\tif err != nil {\n" +
+				"\t\twriteError(w, http.StatusBadRequest, err)\n" +
+				"\t\treturn\n" +
+				"\t}\n" +
+				"\tif err := xsnh.service.Delete(r.Context(), id); err != nil {\n" +
+				"\t\twriteError(w, statusFor(err), err)\n" +
+				"\t\treturn\n" +
+				"\t}\n" +
+				"\tw.WriteHeader(http.StatusNoContent)\n" +
+				"\t// microcli:end\n" +
+				"}\n",
+			"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+				"\t\treturn http.StatusNotFound\n" +
+				"\t}\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+	})
+
+	t.Run("it should return parseable handler and test files", func(t *testing.T) {
+		handler := entity.NewHandler(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		for _, src := range []string{handler.File().String(), handler.TestFile().String()} {
+			if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors); err != nil {
+				utils.Error(t, "a valid go file", err)
+			}
+		}
+	})
+
+	t.Run("it should fail for unknown frameworks", func(t *testing.T) {
+		_, err := entity.NewHandlerWithFramework(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			"martini",
+		)
+		if !errors.Is(err, entity.ErrUnknownFramework) {
+			utils.Error(t, entity.ErrUnknownFramework, err)
+		}
+	})
+}
+
+func TestNewHandlerWithFramework(t *testing.T) {
+	type testCase struct {
+		it        string
+		framework string
+		want      []string
+		wantTest  []string
+	}
+
+	tc := []testCase{
+		{
+			it:        "should register chi routes",
+			framework: "chi",
+			want: []string{
+				"func (xsnh *XptoStructNameHandler) Routes(r chi.Router) {\n",
+				"\tr.Get(\"/xpto-struct-names/{id}\", xsnh.get)\n",
+				"\tid, err := uuid.Parse(chi.URLParam(r, \"id\"))\n",
+			},
+			wantTest: []string{
+				"\t\"net/http/httptest\"\n",
+				"\t\t\treq := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))\n",
+				"\t\t\trouter := chi.NewRouter()\n" +
+					"\t\t\tNewXptoStructNameHandler(fakeXptoStructNameService{err: c.err}).Routes(router)\n" +
+					"\t\t\trec := httptest.NewRecorder()\n" +
+					"\t\t\trouter.ServeHTTP(rec, req)\n",
+			},
+		},
+		{
+			it:        "should register gin routes",
+			framework: "gin",
+			want: []string{
+				"func (xsnh *XptoStructNameHandler) Register(r gin.IRouter) {\n",
+				"\tr.PUT(\"/xpto-struct-names/:id\", xsnh.update)\n",
+				"func (xsnh *XptoStructNameHandler) get(c *gin.Context) {\n",
+				"\tif err := c.ShouldBindJSON(&xptoStructName); err != nil {\n" +
+					"\t\tc.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})\n" +
+					"\t\treturn\n" +
+					"\t}\n",
+			},
+			wantTest: []string{
+				"\t\"net/http/httptest\"\n",
+				"\t\t\treq := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))\n",
+				"\t\t\trouter := gin.New()\n" +
+					"\t\t\tNewXptoStructNameHandler(fakeXptoStructNameService{err: c.err}).Register(router)\n" +
+					"\t\t\trec := httptest.NewRecorder()\n" +
+					"\t\t\trouter.ServeHTTP(rec, req)\n",
+			},
+		},
+		{
+			it:        "should register echo routes",
+			framework: "echo",
+			want: []string{
+				"func (xsnh *XptoStructNameHandler) Register(g *echo.Group) {\n",
+				"\tg.DELETE(\"/xpto-struct-names/:id\", xsnh.delete)\n",
+				"func (xsnh *XptoStructNameHandler) delete(c echo.Context) error {\n",
+				"\treturn c.NoContent(http.StatusNoContent)\n",
+			},
+			wantTest: []string{
+				"\t\"net/http/httptest\"\n",
+				"\t\t\treq := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))\n",
+				"\t\t\te := echo.New()\n" +
+					"\t\t\tNewXptoStructNameHandler(fakeXptoStructNameService{err: c.err}).Register(e.Group(\"\"))\n" +
+					"\t\t\trec := httptest.NewRecorder()\n" +
+					"\t\t\te.ServeHTTP(rec, req)\n",
+			},
+		},
+		{
+			it:        "should register fiber routes",
+			framework: "fiber",
+			want: []string{
+				"func (xsnh *XptoStructNameHandler) Register(r fiber.Router) {\n",
+				"\tr.Post(\"/xpto-struct-names\", xsnh.create)\n",
+				"\txptoStructName, err := xsnh.service.Get(c.UserContext(), id)\n",
+				"\treturn c.Status(http.StatusCreated).JSON(created)\n",
+			},
+			wantTest: []string{
+				"\t\"net/http/httptest\"\n",
+				"\t\t\treq := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))\n",
+				"\t\t\tapp := fiber.New()\n" +
+					"\t\t\tNewXptoStructNameHandler(fakeXptoStructNameService{err: c.err}).Register(app)\n" +
+					"\t\t\tres, err := app.Test(req)\n",
+			},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			handler, err := entity.NewHandlerWithFramework(
+				entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+				"github.com/eduardoths/microservice",
+				c.framework,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if handler.Framework() != c.framework {
+				utils.Error(t, c.framework, handler.Framework())
+			}
+
+			actual := handler.File().String()
+			for _, want := range c.want {
+				if !strings.Contains(actual, want) {
+					utils.Error(t, want, actual)
+				}
+			}
+			actualTest := handler.TestFile().String()
+			for _, want := range c.wantTest {
+				if !strings.Contains(actualTest, want) {
+					utils.Error(t, want, actualTest)
+				}
+			}
+			for _, src := range []string{actual, actualTest} {
+				if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors); err != nil {
+					utils.Error(t, "a valid go file", err)
+				}
+			}
+		})
+	}
+}
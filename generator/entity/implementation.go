@@ -0,0 +1,284 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+type ImplementationConfig struct {
+	PointerReceivers   bool
+	Constructor        bool
+	InterfaceAssertion bool
+	// FunctionalOptions makes the constructor take the dependencies that
+	// are not required as functional options, set to their default first.
+	FunctionalOptions bool
+	Dependencies      []Dependency
+}
+
+func DefaultImplementationConfig() ImplementationConfig {
+	return ImplementationConfig{
+		PointerReceivers:   true,
+		Constructor:        true,
+		InterfaceAssertion: true,
+	}
+}
+
+type Dependency struct {
+	Name   string
+	Type   string
+	Import file.Import
+	// Default is the value of the dependency until its functional option
+	// sets it, its zero value when empty.
+	Default string
+	// Required keeps the dependency a param of the constructor taking
+	// functional options.
+	Required bool
+}
+
+func (d Dependency) field() file.Field {
+	return file.Field{Name: d.Name, Type: d.Type}
+}
+
+func (d Dependency) param() file.Arg {
+	return file.Arg{Name: d.Name, Type: d.Type}
+}
+
+// implementation builds the unexported struct that satisfies a generated
+// interface, along with its constructor and compile-time assertion.
+type implementation struct {
+	iface   EntityName
+	config  ImplementationConfig
+	methods []imethod
+}
+
+func (i implementation) structName() string {
+	return i.iface.CamelCase()
+}
+
+// alias returns the alias of the receivers of the struct.
+func (i implementation) alias() string {
+	methods := make([]file.Method, 0, len(i.methods))
+	for _, imethod := range i.methods {
+		methods = append(methods, imethod.method)
+	}
+	return receiverAlias(i.iface, methods, i.config.Dependencies)
+}
+
+// receiverAlias returns the alias of the receivers of the methods of iface,
+// suffixed when one of their params or results, or a dependency given to
+// the constructor, is named after it, as the single letter alias of the
+// names made of the initials of a keyword could be.
+func receiverAlias(iface EntityName, methods []file.Method, deps []Dependency) string {
+	taken := make(map[string]bool)
+	for _, m := range methods {
+		for _, args := range []file.Args{m.Params, m.Results} {
+			for _, arg := range args {
+				taken[arg.Name] = true
+			}
+		}
+	}
+	for _, dep := range deps {
+		taken[dep.Name] = true
+	}
+	return localName(iface.Alias(), taken)
+}
+
+func (i implementation) receiverName() string {
+	if i.config.PointerReceivers {
+		return "*" + i.structName()
+	}
+	return i.structName()
+}
+
+func (i implementation) constructorName() string {
+	return "New" + i.iface.PascalCase()
+}
+
+// functionalOptions reports whether the constructor takes functional
+// options, which needs a constructor.
+func (i implementation) functionalOptions() bool {
+	return i.config.Constructor && i.config.FunctionalOptions
+}
+
+func (i implementation) optionName() string {
+	return i.iface.PascalCase() + "Option"
+}
+
+// types returns the type of the functional options of the constructor.
+func (i implementation) types() []file.Type {
+	if !i.functionalOptions() {
+		return nil
+	}
+	return []file.Type{{Name: i.optionName(), Type: "func(*" + i.structName() + ")"}}
+}
+
+func (i implementation) imports() file.Imports {
+	imports := make(file.Imports, 0)
+	for _, dep := range i.config.Dependencies {
+		if dep.Import.Path != "" {
+			imports = append(imports, dep.Import)
+		}
+	}
+	return imports
+}
+
+func (i implementation) vars() []file.Var {
+	if !i.config.InterfaceAssertion {
+		return nil
+	}
+	value := i.structName() + "{}"
+	if i.config.PointerReceivers {
+		value = "(" + i.receiverName() + ")(nil)"
+	}
+	return []file.Var{
+		{Name: "_", Type: i.iface.PascalCase(), Value: value},
+	}
+}
+
+func (i implementation) build() file.Struct {
+	s := file.Struct{
+		Name:            i.structName(),
+		Fields:          make([]file.Field, 0, len(i.config.Dependencies)),
+		Implementations: make([]file.Implementation, 0, len(i.methods)+1),
+	}
+	for _, dep := range i.config.Dependencies {
+		s.Fields = append(s.Fields, dep.field())
+	}
+
+	if i.functionalOptions() {
+		s.Implementations = append(s.Implementations, i.optionsConstructor())
+		for _, dep := range i.config.Dependencies {
+			if !dep.Required {
+				s.Implementations = append(s.Implementations, i.option(dep))
+			}
+		}
+	} else if i.config.Constructor {
+		s.Implementations = append(s.Implementations, i.constructor())
+	}
+	for _, imethod := range i.methods {
+		s.Implementations = append(s.Implementations, file.Implementation{
+			StructAlias: i.alias(),
+			StructName:  i.receiverName(),
+			Func:        imethod.method,
+			CodeLines:   imethod.implementation,
+			UserRegion:  true,
+		})
+	}
+	return s
+}
+
+func (i implementation) constructor() file.Implementation {
+	params := make(file.Args, 0, len(i.config.Dependencies))
+	for _, dep := range i.config.Dependencies {
+		params = append(params, dep.param())
+	}
+
+	literal := i.literalStart()
+	codeLines := make([]string, 0, len(i.config.Dependencies)+2)
+	if len(i.config.Dependencies) == 0 {
+		codeLines = append(codeLines, "return "+literal+"}")
+	} else {
+		codeLines = append(codeLines, "return "+literal)
+		for _, dep := range i.config.Dependencies {
+			codeLines = append(codeLines, "\t"+dep.Name+": "+dep.Name+",")
+		}
+		codeLines = append(codeLines, "}")
+	}
+
+	return file.Implementation{
+		Func: file.Method{
+			Name:    i.constructorName(),
+			Params:  params,
+			Results: file.Args{{Type: i.iface.PascalCase()}},
+		},
+		CodeLines: codeLines,
+	}
+}
+
+// optionsConstructor returns the constructor taking the required
+// dependencies and the functional options setting the other ones.
+func (i implementation) optionsConstructor() file.Implementation {
+	params := make(file.Args, 0, len(i.config.Dependencies)+1)
+	values := make([]string, 0, len(i.config.Dependencies))
+	for _, dep := range i.config.Dependencies {
+		switch {
+		case dep.Required:
+			params = append(params, dep.param())
+			values = append(values, "\t"+dep.Name+": "+dep.Name+",")
+		case dep.Default != "":
+			values = append(values, "\t"+dep.Name+": "+dep.Default+",")
+		}
+	}
+	taken := make(map[string]bool, len(i.config.Dependencies)+2)
+	for _, dep := range i.config.Dependencies {
+		taken[dep.Name] = true
+	}
+	opts := localName("opts", taken)
+	taken[opts] = true
+	opt := localName("opt", taken)
+	taken[opt] = true
+	alias := localName(i.alias(), taken)
+	params = append(params, file.Arg{Name: opts, Type: i.optionName(), Variadic: true})
+
+	codeLines := []string{alias + " := " + i.literalStart() + "}"}
+	if len(values) > 0 {
+		codeLines = append([]string{alias + " := " + i.literalStart()}, values...)
+		codeLines = append(codeLines, "}")
+	}
+	target := alias
+	if !i.config.PointerReceivers {
+		target = "&" + alias
+	}
+
+	return file.Implementation{
+		Func: file.Method{
+			Name:    i.constructorName(),
+			Params:  params,
+			Results: file.Args{{Type: i.iface.PascalCase()}},
+		},
+		CodeLines: codeLines,
+		Body: file.Block{
+			file.Range("_, "+opt, file.Code(opts),
+				file.Eval(file.Code(opt).Call(file.Code(target))),
+			),
+			file.Return(file.Code(alias)),
+		},
+	}
+}
+
+// option returns the functional option setting the dependency, the param of
+// the closure being named after the receivers unless the dependency is.
+func (i implementation) option(dep Dependency) file.Implementation {
+	alias := localName(i.alias(), map[string]bool{dep.Name: true})
+	return file.Implementation{
+		Func: file.Method{
+			Name:    "With" + NewEntityName(dep.Name, "", "").PascalCase(),
+			Params:  file.Args{dep.param()},
+			Results: file.Args{{Type: i.optionName()}},
+		},
+		CodeLines: []string{
+			"return func(" + alias + " *" + i.structName() + ") {",
+			"\t" + alias + "." + dep.Name + " = " + dep.Name,
+			"}",
+		},
+	}
+}
+
+// literalStart opens a composite literal of the struct, addressed when its
+// methods have pointer receivers.
+func (i implementation) literalStart() string {
+	if i.config.PointerReceivers {
+		return "&" + i.structName() + "{"
+	}
+	return i.structName() + "{"
+}
+
+func notFoundVar(structName EntityName) file.Var {
+	message := strings.ReplaceAll(structName.SnakeCase(), "_", " ") + " not found"
+	return file.Var{
+		Name:  "ErrNotFound",
+		Value: "errors.New(" + strconv.Quote(message) + ")",
+	}
+}
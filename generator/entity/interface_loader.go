@@ -0,0 +1,172 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+var (
+	ErrInterfaceNotFound = errors.New("interface not found")
+	ErrForeignEmbed      = errors.New("interface embeds an interface of another package")
+)
+
+// declaredInterface is an interface type declared in a file of a package.
+type declaredInterface struct {
+	iface *ast.InterfaceType
+	file  *ast.File
+}
+
+// LoadInterface reads the interface declaration named after iface from the
+// Go files found in its directory under root, along with the imports its
+// methods refer to. The methods of the interfaces it embeds are loaded in
+// place of the embeddings, which must be declared in the same package.
+func LoadInterface(root string, iface EntityName) (file.Interface, file.Imports, error) {
+	dir := filepath.Join(root, iface.dirPath)
+	files, err := parseDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return file.Interface{}, nil, fmt.Errorf("%w: %s, %s does not exist", ErrInterfaceNotFound, iface.PascalCase(), dir)
+	}
+	if err != nil {
+		return file.Interface{}, nil, err
+	}
+
+	declared := make(map[string]declaredInterface)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if interfaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					declared[typeSpec.Name.Name] = declaredInterface{iface: interfaceType, file: f}
+				}
+			}
+		}
+	}
+	if _, ok := declared[iface.PascalCase()]; !ok {
+		return file.Interface{}, nil, fmt.Errorf("%w: %s in %s", ErrInterfaceNotFound, iface.PascalCase(), dir)
+	}
+
+	loader := interfaceLoader{declared: declared, loaded: make(map[string]bool), seen: make(map[string]bool)}
+	if err := loader.load(iface.PascalCase()); err != nil {
+		return file.Interface{}, nil, err
+	}
+	return file.Interface{Name: iface.PascalCase(), Methods: loader.methods}, loader.imports, nil
+}
+
+type interfaceLoader struct {
+	declared map[string]declaredInterface
+	loaded   map[string]bool
+	seen     map[string]bool
+	methods  []file.Method
+	imports  file.Imports
+}
+
+// load adds the methods of the interface declared as name, once however
+// many times it is embedded.
+func (l *interfaceLoader) load(name string) error {
+	if l.loaded[name] {
+		return nil
+	}
+	l.loaded[name] = true
+	d := l.declared[name]
+	for _, field := range d.iface.Methods.List {
+		switch typ := field.Type.(type) {
+		case *ast.FuncType:
+			for _, methodName := range field.Names {
+				l.add(file.Method{
+					Name:    methodName.Name,
+					Params:  funcArgs(typ.Params),
+					Results: funcArgs(typ.Results),
+				}, typ, d.file)
+			}
+		case *ast.Ident:
+			if typ.Name == "error" {
+				l.add(file.Method{Name: "Error", Results: file.Args{{Type: "string"}}}, typ, d.file)
+				continue
+			}
+			if _, ok := l.declared[typ.Name]; !ok {
+				return fmt.Errorf("%w: %s embedded in %s", ErrInterfaceNotFound, typ.Name, name)
+			}
+			if err := l.load(typ.Name); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: %s embeds %s", ErrForeignEmbed, name, types.ExprString(field.Type))
+		}
+	}
+	return nil
+}
+
+// add appends the method unless an embedded interface declared it already,
+// along with the imports of the packages its signature refers to.
+func (l *interfaceLoader) add(m file.Method, typ ast.Node, f *ast.File) {
+	if l.seen[m.Name] {
+		return
+	}
+	l.seen[m.Name] = true
+	l.methods = append(l.methods, m)
+
+	ast.Inspect(typ, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok {
+			if imp, ok := fileImport(f, pkg.Name); ok {
+				l.imports = append(l.imports, imp)
+			}
+		}
+		return false
+	})
+}
+
+// fileImport returns the import of f referred to by name.
+func fileImport(f *ast.File, name string) (file.Import, bool) {
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		imp := file.Import{Path: path}
+		if spec.Name != nil {
+			imp.Name = spec.Name.Name
+		}
+		if imp.PackageName() == name {
+			return imp, true
+		}
+	}
+	return file.Import{}, false
+}
+
+func funcArgs(fields *ast.FieldList) file.Args {
+	args := make(file.Args, 0)
+	if fields == nil {
+		return args
+	}
+	for _, field := range fields.List {
+		arg := file.Arg{Type: types.ExprString(field.Type)}
+		if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+			arg = file.Arg{Type: types.ExprString(ellipsis.Elt), Variadic: true}
+		}
+		if len(field.Names) == 0 {
+			args = append(args, arg)
+			continue
+		}
+		for _, name := range field.Names {
+			arg.Name = name.Name
+			args = append(args, arg)
+		}
+	}
+	return args
+}
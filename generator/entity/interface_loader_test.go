@@ -0,0 +1,109 @@
+package entity_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestLoadInterface(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "src", "repositories", "xpto_struct")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"xpto_struct_repository.go": "package xptostruct\n\n" +
+			"import (\n\t\"context\"\n\t\"io\"\n\n\tst \"github.com/e/svc/src/structs\"\n)\n\n" +
+			"type XptoStructRepository interface {\n" +
+			"\tReader\n" +
+			"\tGet(ctx context.Context, ids ...string) (st.XptoStruct, error)\n" +
+			"\tFind(context.Context, string) error\n" +
+			"}\n\n" +
+			"type Foreign interface {\n" +
+			"\tio.Closer\n" +
+			"}\n\n" +
+			"type Missing interface {\n" +
+			"\tUnknown\n" +
+			"}\n",
+		"reader.go": "package xptostruct\n\n" +
+			"import \"time\"\n\n" +
+			"type Reader interface {\n" +
+			"\terror\n" +
+			"\tRead(since time.Time) (n int, err error)\n" +
+			"}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	name := func(iface string) entity.EntityName {
+		return entity.NewEntityName(iface, "src/repositories/xpto_struct", "github.com/e/svc")
+	}
+
+	t.Run("it should load the methods of the interface and of the ones it embeds", func(t *testing.T) {
+		iface, imports, err := entity.LoadInterface(root, name("XptoStructRepository"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := file.Interface{
+			Name: "XptoStructRepository",
+			Methods: []file.Method{
+				{Name: "Error", Results: file.Args{{Type: "string"}}},
+				{
+					Name:    "Read",
+					Params:  file.Args{{Name: "since", Type: "time.Time"}},
+					Results: file.Args{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}},
+				},
+				{
+					Name:    "Get",
+					Params:  file.Args{{Name: "ctx", Type: "context.Context"}, {Name: "ids", Type: "string", Variadic: true}},
+					Results: file.Args{{Type: "st.XptoStruct"}, {Type: "error"}},
+				},
+				{
+					Name:    "Find",
+					Params:  file.Args{{Type: "context.Context"}, {Type: "string"}},
+					Results: file.Args{{Type: "error"}},
+				},
+			},
+		}
+		if !reflect.DeepEqual(want, iface) {
+			utils.Error(t, want, iface)
+		}
+		wantImports := file.Imports{
+			{Path: "time"},
+			{Path: "context"},
+			{Name: "st", Path: "github.com/e/svc/src/structs"},
+		}
+		if wantImports.String() != imports.String() {
+			utils.Error(t, wantImports, imports)
+		}
+	})
+
+	t.Run("it should fail when the interface does not exist", func(t *testing.T) {
+		for _, missing := range []entity.EntityName{
+			name("Unknown"),
+			name("Missing"),
+			entity.NewEntityName("XptoStructRepository", "src/repositories/missing", "github.com/e/svc"),
+		} {
+			_, _, err := entity.LoadInterface(root, missing)
+			if !errors.Is(err, entity.ErrInterfaceNotFound) {
+				utils.Error(t, entity.ErrInterfaceNotFound, err)
+			}
+		}
+	})
+
+	t.Run("it should fail when the interface embeds one of another package", func(t *testing.T) {
+		_, _, err := entity.LoadInterface(root, name("Foreign"))
+		if !errors.Is(err, entity.ErrForeignEmbed) {
+			utils.Error(t, entity.ErrForeignEmbed, err)
+		}
+	})
+}
@@ -0,0 +1,362 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/utils"
+)
+
+var ErrInvalidJSONSample = errors.New("invalid JSON sample")
+
+type jsonKind int
+
+const (
+	jsonNull jsonKind = iota
+	jsonBool
+	jsonInt
+	jsonFloat
+	jsonTime
+	jsonString
+	jsonObject
+	jsonArray
+	jsonAny
+)
+
+// jsonType is the type inferred from one or more JSON values.
+type jsonType struct {
+	kind     jsonKind
+	nullable bool
+	fields   []*jsonField
+	elem     *jsonType
+}
+
+type jsonField struct {
+	key      string
+	typ      *jsonType
+	optional bool
+}
+
+// JSONStruct infers the struct tree of the JSON objects given as samples.
+type JSONStruct struct {
+	structName EntityName
+	root       string
+	tags       TagNaming
+	structs    []file.Struct
+	names      map[string]bool
+
+	Imports file.Imports
+}
+
+func NewJSONStruct(structName EntityName, samples [][]byte) (JSONStruct, error) {
+	return NewJSONStructWithTags(structName, samples, DefaultTagNaming())
+}
+
+// NewJSONStructWithTags returns the struct tree of the samples, its fields
+// tagged with the keys of tags besides json, which holds the keys of the
+// samples.
+func NewJSONStructWithTags(structName EntityName, samples [][]byte, tags TagNaming) (JSONStruct, error) {
+	var root *jsonType
+	for i, sample := range samples {
+		decoder := json.NewDecoder(bytes.NewReader(sample))
+		decoder.UseNumber()
+		typ, err := decodeJSONType(decoder)
+		if err != nil {
+			return JSONStruct{}, fmt.Errorf("%w: sample %d: %v", ErrInvalidJSONSample, i+1, err)
+		}
+		if _, err := decoder.Token(); err != io.EOF {
+			return JSONStruct{}, fmt.Errorf("%w: sample %d: trailing data", ErrInvalidJSONSample, i+1)
+		}
+
+		// arrays of objects hold one sample per element
+		if typ.kind == jsonArray {
+			if typ.elem == nil || typ.elem.kind != jsonObject {
+				return JSONStruct{}, fmt.Errorf("%w: sample %d is an array of %s, expected objects", ErrInvalidJSONSample, i+1, typ.elem.describe())
+			}
+			typ = typ.elem
+		}
+		if typ.kind != jsonObject {
+			return JSONStruct{}, fmt.Errorf("%w: sample %d is %s, expected an object", ErrInvalidJSONSample, i+1, typ.describe())
+		}
+		root = mergeJSONTypes(root, typ)
+	}
+	if root == nil {
+		return JSONStruct{}, fmt.Errorf("%w: no samples", ErrInvalidJSONSample)
+	}
+
+	s := JSONStruct{
+		structName: structName,
+		root:       structName.PascalCase(),
+		tags:       tags,
+		names:      make(map[string]bool),
+		Imports:    make(file.Imports, 0),
+	}
+	s.names[structName.PascalCase()] = true
+	if err := s.buildStruct(structName.PascalCase(), root); err != nil {
+		return JSONStruct{}, fmt.Errorf("%w: %v", ErrInvalidJSONSample, err)
+	}
+	return s, nil
+}
+
+func (s JSONStruct) FilePath() string {
+	return s.structName.FilePath()
+}
+
+func (s JSONStruct) File() file.File {
+	return file.File{
+		Package: s.structName.ImportName(),
+		Imports: s.Imports,
+		Structs: s.structs,
+	}
+}
+
+// buildStruct appends the struct for the object type, followed by the structs
+// of its nested objects.
+func (s *JSONStruct) buildStruct(name string, typ *jsonType) error {
+	index := len(s.structs)
+	s.structs = append(s.structs, file.Struct{Name: name})
+
+	fields := make([]file.Field, 0, len(typ.fields))
+	used := make(map[string]bool, len(typ.fields))
+	for _, field := range typ.fields {
+		fieldName := NewEntityName(field.key, "", "").FieldName()
+		goName := fieldName
+		for i := 2; used[goName]; i++ {
+			goName = fieldName + strconv.Itoa(i)
+		}
+		used[goName] = true
+		jsonTag := field.key
+		if field.optional {
+			jsonTag += ",omitempty"
+		}
+		goType, err := s.goType(name, goName, field.typ, field.optional)
+		if err != nil {
+			return err
+		}
+		// null values are valid ones, the required rule would reject
+		required := !field.optional && !field.typ.nullable && requirable(goType)
+		tag, err := s.tags.Tag(NewEntityName(goName, "", ""), field.optional, required, map[string]string{JSON_TAG: jsonTag})
+		if err != nil {
+			return err
+		}
+		fields = append(fields, file.Field{Name: goName, Type: goType, Tag: tag.String()})
+	}
+	s.structs[index].Fields = fields
+	return nil
+}
+
+func (s *JSONStruct) goType(parent string, fieldName string, typ *jsonType, optional bool) (string, error) {
+	goType := ""
+	switch typ.kind {
+	case jsonNull, jsonAny:
+		return "any", nil
+	case jsonBool:
+		goType = "bool"
+	case jsonInt:
+		goType = "int"
+	case jsonFloat:
+		goType = "float64"
+	case jsonTime:
+		goType = "time.Time"
+		s.Imports = append(s.Imports, file.Import{Path: "time"})
+	case jsonString:
+		goType = "string"
+	case jsonArray:
+		if typ.elem == nil {
+			return "[]any", nil
+		}
+		elemType, err := s.goType(parent, utils.Singularize(fieldName), typ.elem, false)
+		return "[]" + elemType, err
+	case jsonObject:
+		goType = s.nestedName(parent, fieldName)
+		if err := s.buildStruct(goType, typ); err != nil {
+			return "", err
+		}
+	}
+
+	if typ.nullable || optional {
+		return "*" + goType, nil
+	}
+	return goType, nil
+}
+
+// nestedName names a nested struct after its field, prefixed with the root
+// struct name so that the structs of other samples of the package, which may
+// have the same keys, are not redeclared. The parent name is prefixed
+// instead when the name is taken.
+func (s *JSONStruct) nestedName(parent string, fieldName string) string {
+	candidates := []string{s.root + fieldName, parent + fieldName}
+	for _, name := range candidates {
+		if !s.names[name] {
+			s.names[name] = true
+			return name
+		}
+	}
+	for i := 2; ; i++ {
+		name := parent + fieldName + strconv.Itoa(i)
+		if !s.names[name] {
+			s.names[name] = true
+			return name
+		}
+	}
+}
+
+func decodeJSONType(decoder *json.Decoder) (*jsonType, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case nil:
+		return &jsonType{kind: jsonNull, nullable: true}, nil
+	case bool:
+		return &jsonType{kind: jsonBool}, nil
+	case json.Number:
+		if strings.ContainsAny(value.String(), ".eE") {
+			return &jsonType{kind: jsonFloat}, nil
+		}
+		return &jsonType{kind: jsonInt}, nil
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return &jsonType{kind: jsonTime}, nil
+		}
+		return &jsonType{kind: jsonString}, nil
+	case json.Delim:
+		if value == '[' {
+			typ := &jsonType{kind: jsonArray}
+			for decoder.More() {
+				elem, err := decodeJSONType(decoder)
+				if err != nil {
+					return nil, err
+				}
+				typ.elem = mergeJSONTypes(typ.elem, elem)
+			}
+			_, err := decoder.Token()
+			return typ, err
+		}
+
+		typ := &jsonType{kind: jsonObject}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			fieldType, err := decodeJSONType(decoder)
+			if err != nil {
+				return nil, err
+			}
+			// encoding/json cannot map an empty key, the empty json tag
+			// standing for the field name
+			if key == "" {
+				return nil, errors.New("empty keys cannot be mapped to struct fields")
+			}
+			typ.fields = append(typ.fields, &jsonField{key: key.(string), typ: fieldType})
+		}
+		_, err := decoder.Token()
+		return typ, err
+	}
+	return nil, fmt.Errorf("unexpected token %v", token)
+}
+
+// mergeJSONTypes widens a to also describe b. Object fields missing from one
+// of them become optional, nulls make the type nullable and mismatched kinds
+// fall back to any.
+func mergeJSONTypes(a *jsonType, b *jsonType) *jsonType {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.kind == jsonNull:
+		merged := *b
+		merged.nullable = true
+		return &merged
+	case b.kind == jsonNull:
+		merged := *a
+		merged.nullable = true
+		return &merged
+	}
+
+	merged := &jsonType{kind: a.kind, nullable: a.nullable || b.nullable}
+	switch {
+	case a.kind == b.kind && a.kind == jsonObject:
+		merged.fields = mergeJSONFields(a.fields, b.fields)
+	case a.kind == b.kind && a.kind == jsonArray:
+		merged.elem = mergeJSONTypes(a.elem, b.elem)
+	case a.kind == b.kind:
+	case isJSONNumber(a.kind) && isJSONNumber(b.kind):
+		merged.kind = jsonFloat
+	case isJSONText(a.kind) && isJSONText(b.kind):
+		merged.kind = jsonString
+	default:
+		merged.kind = jsonAny
+	}
+	return merged
+}
+
+func mergeJSONFields(a []*jsonField, b []*jsonField) []*jsonField {
+	others := make(map[string]*jsonField, len(b))
+	for _, field := range b {
+		others[field.key] = field
+	}
+
+	merged := make([]*jsonField, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a))
+	for _, field := range a {
+		seen[field.key] = true
+		other, ok := others[field.key]
+		if !ok {
+			merged = append(merged, &jsonField{key: field.key, typ: field.typ, optional: true})
+			continue
+		}
+		merged = append(merged, &jsonField{
+			key:      field.key,
+			typ:      mergeJSONTypes(field.typ, other.typ),
+			optional: field.optional || other.optional,
+		})
+	}
+	for _, field := range b {
+		if !seen[field.key] {
+			merged = append(merged, &jsonField{key: field.key, typ: field.typ, optional: true})
+		}
+	}
+	return merged
+}
+
+// describe names the kind of JSON value of typ, for error messages.
+func (typ *jsonType) describe() string {
+	if typ == nil {
+		return "nothing"
+	}
+	switch typ.kind {
+	case jsonNull:
+		return "null"
+	case jsonBool:
+		return "booleans"
+	case jsonInt, jsonFloat:
+		return "numbers"
+	case jsonTime, jsonString:
+		return "strings"
+	case jsonObject:
+		return "objects"
+	case jsonArray:
+		return "arrays"
+	}
+	return "mixed values"
+}
+
+func isJSONNumber(kind jsonKind) bool {
+	return kind == jsonInt || kind == jsonFloat
+}
+
+func isJSONText(kind jsonKind) bool {
+	return kind == jsonTime || kind == jsonString
+}
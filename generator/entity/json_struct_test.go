@@ -0,0 +1,176 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestNewJSONStruct(t *testing.T) {
+	structName := entity.NewEntityName("ThirdPartyOrder", "src/structs", "github.com/eduardoths/microservice")
+
+	t.Run("it should infer nested structs from a sample", func(t *testing.T) {
+		sample := `{
+			"id": 1,
+			"customerName": "xpto",
+			"total": 10.5,
+			"created_at": "2024-01-02T03:04:05Z",
+			"shipping": {"street": "a", "geo": {"lat": 1.5}},
+			"items": [{"sku": "a", "qty": 1}, {"sku": "b"}],
+			"tags": [],
+			"note": null
+		}`
+
+		s, err := entity.NewJSONStruct(structName, [][]byte{[]byte(sample)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "src/structs/third_party_order.go"; want != s.FilePath() {
+			utils.Error(t, want, s.FilePath())
+		}
+
+		actual := s.File().String()
+		want := "package structs\n\n" +
+			"import \"time\"\n\n" +
+			"type ThirdPartyOrder struct {\n" +
+			"\tID int `json:\"id\"`\n" +
+			"\tCustomerName string `json:\"customerName\"`\n" +
+			"\tTotal float64 `json:\"total\"`\n" +
+			"\tCreatedAt time.Time `json:\"created_at\"`\n" +
+			"\tShipping ThirdPartyOrderShipping `json:\"shipping\"`\n" +
+			"\tItems []ThirdPartyOrderItem `json:\"items\"`\n" +
+			"\tTags []any `json:\"tags\"`\n" +
+			"\tNote any `json:\"note\"`\n" +
+			"}\n\n" +
+			"type ThirdPartyOrderShipping struct {\n" +
+			"\tStreet string `json:\"street\"`\n" +
+			"\tGeo ThirdPartyOrderGeo `json:\"geo\"`\n" +
+			"}\n\n" +
+			"type ThirdPartyOrderGeo struct {\n" +
+			"\tLat float64 `json:\"lat\"`\n" +
+			"}\n\n" +
+			"type ThirdPartyOrderItem struct {\n" +
+			"\tSku string `json:\"sku\"`\n" +
+			"\tQty *int `json:\"qty,omitempty\"`\n" +
+			"}\n"
+		if want != actual {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should widen types across samples", func(t *testing.T) {
+		samples := [][]byte{
+			[]byte(`{"id": 1, "total": 10, "created_at": "2024-01-02T03:04:05Z", "code": 1, "shipping": {"street": "a"}}`),
+			[]byte(`[{"id": 2, "total": 10.5, "created_at": "yesterday", "code": "A1", "shipping": null, "tags": ["x"]}]`),
+		}
+
+		s, err := entity.NewJSONStruct(structName, samples)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := s.File().String()
+		want := "package structs\n\n" +
+			"type ThirdPartyOrder struct {\n" +
+			"\tID int `json:\"id\"`\n" +
+			"\tTotal float64 `json:\"total\"`\n" +
+			"\tCreatedAt string `json:\"created_at\"`\n" +
+			"\tCode any `json:\"code\"`\n" +
+			"\tShipping *ThirdPartyOrderShipping `json:\"shipping\"`\n" +
+			"\tTags []string `json:\"tags,omitempty\"`\n" +
+			"}\n\n" +
+			"type ThirdPartyOrderShipping struct {\n" +
+			"\tStreet string `json:\"street\"`\n" +
+			"}\n"
+		if want != actual {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should prefix nested struct names with the root and the parent names", func(t *testing.T) {
+		sample := `{"user": {"name": "a"}, "manager": {"user": {"email": "b"}}}`
+
+		s, err := entity.NewJSONStruct(structName, [][]byte{[]byte(sample)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := s.File().String()
+		want := "package structs\n\n" +
+			"type ThirdPartyOrder struct {\n" +
+			"\tUser ThirdPartyOrderUser `json:\"user\"`\n" +
+			"\tManager ThirdPartyOrderManager `json:\"manager\"`\n" +
+			"}\n\n" +
+			"type ThirdPartyOrderUser struct {\n" +
+			"\tName string `json:\"name\"`\n" +
+			"}\n\n" +
+			"type ThirdPartyOrderManager struct {\n" +
+			"\tUser ThirdPartyOrderManagerUser `json:\"user\"`\n" +
+			"}\n\n" +
+			"type ThirdPartyOrderManagerUser struct {\n" +
+			"\tEmail string `json:\"email\"`\n" +
+			"}\n"
+		if want != actual {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should populate the other tags from the field names", func(t *testing.T) {
+		sample := `[{"customerName": "xpto", "note": "a"}, {"customerName": "xpto"}]`
+
+		s, err := entity.NewJSONStructWithTags(structName, [][]byte{[]byte(sample)}, entity.TagNaming{
+			entity.JSON_TAG:     entity.NAMING_SNAKE,
+			"db":                entity.NAMING_SNAKE,
+			entity.VALIDATE_TAG: entity.NAMING_SNAKE,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := s.File().String()
+		want := "package structs\n\n" +
+			"type ThirdPartyOrder struct {\n" +
+			"\tCustomerName string `json:\"customerName\" db:\"customer_name\" validate:\"required\"`\n" +
+			"\tNote *string `json:\"note,omitempty\" db:\"note\"`\n" +
+			"}\n"
+		if want != actual {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should describe the values of arrays of scalars", func(t *testing.T) {
+		_, err := entity.NewJSONStruct(structName, [][]byte{[]byte(`[1, 2.5]`)})
+		want := "invalid JSON sample: sample 1 is an array of numbers, expected objects"
+		if err == nil || err.Error() != want {
+			utils.Error(t, want, err)
+		}
+	})
+
+	t.Run("it should fail for invalid samples", func(t *testing.T) {
+		type testCase struct {
+			it      string
+			samples [][]byte
+		}
+
+		tc := []testCase{
+			{it: "should fail without samples"},
+			{it: "should fail with malformed json", samples: [][]byte{[]byte(`{"id": `)}},
+			{it: "should fail with scalar samples", samples: [][]byte{[]byte(`1`)}},
+			{it: "should fail with arrays of scalars", samples: [][]byte{[]byte(`[1, 2]`)}},
+			{it: "should fail with empty arrays", samples: [][]byte{[]byte(`[]`)}},
+			{it: "should fail with empty keys", samples: [][]byte{[]byte(`{"": 1}`)}},
+			{it: "should fail with trailing data", samples: [][]byte{[]byte(`{} {}`)}},
+		}
+
+		for _, c := range tc {
+			t.Run(c.it, func(t *testing.T) {
+				_, err := entity.NewJSONStruct(structName, c.samples)
+				if !errors.Is(err, entity.ErrInvalidJSONSample) {
+					utils.Error(t, entity.ErrInvalidJSONSample, err)
+				}
+			})
+		}
+	})
+}
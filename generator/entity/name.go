@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"go/token"
 	"strings"
 	"unicode"
 
@@ -28,6 +29,34 @@ func (en EntityName) PascalCase() string {
 	return strings.ReplaceAll(title, " ", "")
 }
 
+// goInitialisms are written in upper case in Go field names.
+var goInitialisms = map[string]bool{
+	"id": true, "uuid": true, "url": true, "uri": true, "api": true, "http": true,
+	"https": true, "json": true, "xml": true, "html": true, "ip": true, "sql": true,
+}
+
+// FieldName converts a column or JSON key name to an exported Go name,
+// upper casing the common initialisms.
+func (en EntityName) FieldName() string {
+	words := strings.FieldsFunc(en.SnakeCase(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var sb strings.Builder
+	for _, word := range words {
+		switch {
+		case goInitialisms[word]:
+			sb.WriteString(strings.ToUpper(word))
+		default:
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	name := sb.String()
+	if name == "" || !isGoName(name) {
+		return "Field" + name
+	}
+	return name
+}
+
 func (en EntityName) CamelCase() string {
 	pascal := en.PascalCase()
 	pascalRunes := []rune(pascal)
@@ -66,10 +95,22 @@ func (en EntityName) FilePath() string {
 	return utils.MergePaths(en.dirPath, en.SnakeCase()) + ".go"
 }
 
+func (en EntityName) Dir() string {
+	return en.dirPath
+}
+
 func (en EntityName) SnakeCase() string {
 	return utils.ToSnakeCase(en.name)
 }
 
+func (en EntityName) KebabCase() string {
+	return strings.ReplaceAll(en.SnakeCase(), "_", "-")
+}
+
+func (en EntityName) Plural() EntityName {
+	return NewEntityName(utils.Pluralize(en.name), en.dirPath, en.basePkg)
+}
+
 func (en EntityName) Alias() string {
 	pascalCase := en.PascalCase()
 	pascalRunes := []rune(pascalCase)
@@ -80,5 +121,11 @@ func (en EntityName) Alias() string {
 			upperPascalCaseRunes = append(upperPascalCaseRunes, r)
 		}
 	}
-	return strings.ToLower(string(upperPascalCaseRunes))
+	alias := strings.ToLower(string(upperPascalCaseRunes))
+	// receivers cannot be named after keywords, as fakeOrderRepository
+	// would be
+	if token.IsKeyword(alias) {
+		return alias[:1]
+	}
+	return alias
 }
@@ -220,6 +220,11 @@ func TestEntityName_Alias(t *testing.T) {
 			in:   entity.NewEntityName("an_example_struct_repository", "", ""),
 			want: "aesr",
 		},
+		{
+			it:   "should not return a keyword",
+			in:   entity.NewEntityName("fakeOrderRepository", "", ""),
+			want: "f",
+		},
 	}
 
 	for _, c := range tc {
@@ -231,3 +236,78 @@ func TestEntityName_Alias(t *testing.T) {
 		})
 	}
 }
+
+func TestEntityName_KebabCase(t *testing.T) {
+	type testCase struct {
+		it   string
+		in   entity.EntityName
+		want string
+	}
+
+	tc := []testCase{
+		{
+			it:   "should return PascalCase names as kebab-case",
+			in:   entity.NewEntityName("XptoStruct", "", ""),
+			want: "xpto-struct",
+		},
+		{
+			it:   "should return snake_case names as kebab-case",
+			in:   entity.NewEntityName("xpto_struct", "", ""),
+			want: "xpto-struct",
+		},
+		{
+			it:   "should return plural names as kebab-case",
+			in:   entity.NewEntityName("Category", "", "").Plural(),
+			want: "categories",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			actual := c.in.KebabCase()
+			if c.want != actual {
+				utils.Error(t, c.want, actual)
+			}
+		})
+	}
+}
+
+func TestEntityName_FieldName(t *testing.T) {
+	type testCase struct {
+		it   string
+		in   entity.EntityName
+		want string
+	}
+
+	tc := []testCase{
+		{
+			it:   "should return camelCase keys as PascalCase",
+			in:   entity.NewEntityName("customerName", "", ""),
+			want: "CustomerName",
+		},
+		{
+			it:   "should upper case initialisms",
+			in:   entity.NewEntityName("customer_id", "", ""),
+			want: "CustomerID",
+		},
+		{
+			it:   "should drop the characters not allowed in identifiers",
+			in:   entity.NewEntityName("shipping-address.zip", "", ""),
+			want: "ShippingAddressZip",
+		},
+		{
+			it:   "should prefix names not starting with a letter",
+			in:   entity.NewEntityName("2fa", "", ""),
+			want: "Field2fa",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			actual := c.in.FieldName()
+			if c.want != actual {
+				utils.Error(t, c.want, actual)
+			}
+		})
+	}
+}
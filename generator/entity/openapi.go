@@ -0,0 +1,332 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/openapi"
+)
+
+var (
+	ErrUnsupportedSchemaType = errors.New("unsupported OpenAPI schema type")
+	ErrInvalidSchemaType     = errors.New("invalid OpenAPI schema type mapping")
+)
+
+const (
+	OPENAPI_VERSION = "1.0.0"
+	ERROR_SCHEMA    = "Error"
+)
+
+// SchemaTypes maps Go types to the OpenAPI schema describing their JSON
+// encoding.
+type SchemaTypes map[string]openapi.Schema
+
+func DefaultSchemaTypes() SchemaTypes {
+	return SchemaTypes{
+		"string":        {Type: "string"},
+		"bool":          {Type: "boolean"},
+		"int":           {Type: "integer"},
+		"int8":          {Type: "integer", Format: "int32"},
+		"int16":         {Type: "integer", Format: "int32"},
+		"int32":         {Type: "integer", Format: "int32"},
+		"int64":         {Type: "integer", Format: "int64"},
+		"uint":          {Type: "integer"},
+		"uint8":         {Type: "integer", Format: "int32"},
+		"uint16":        {Type: "integer", Format: "int32"},
+		"uint32":        {Type: "integer", Format: "int64"},
+		"uint64":        {Type: "integer", Format: "int64"},
+		"float32":       {Type: "number", Format: "float"},
+		"float64":       {Type: "number", Format: "double"},
+		"[]byte":        {Type: "string", Format: "byte"},
+		"any":           {},
+		"interface{}":   {},
+		"uuid.UUID":     {Type: "string", Format: "uuid"},
+		"time.Time":     {Type: "string", Format: "date-time"},
+		"time.Duration": {Type: "integer", Format: "int64"},
+	}
+}
+
+// ParseSchemaType parses a mapping in the GoType=type[:format] form.
+func ParseSchemaType(raw string) (string, openapi.Schema, error) {
+	goType, schemaType, ok := strings.Cut(raw, "=")
+	if !ok || goType == "" || schemaType == "" {
+		return "", openapi.Schema{}, fmt.Errorf("%w: %q, expected GoType=type[:format]", ErrInvalidSchemaType, raw)
+	}
+	typ, format, _ := strings.Cut(schemaType, ":")
+	return goType, openapi.Schema{Type: typ, Format: format}, nil
+}
+
+// resolve returns the schema for goType. Types named after one of the
+// components are referenced instead of inlined.
+func (types SchemaTypes) resolve(goType string, components map[string]file.Struct) (openapi.Schema, error) {
+	if schema, ok := types[goType]; ok {
+		return schema, nil
+	}
+	if _, ok := components[goType]; ok {
+		return openapi.Ref(goType), nil
+	}
+
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		schema, err := types.resolve(goType[1:], components)
+		if err != nil || schema.Ref != "" {
+			return schema, err
+		}
+		schema.Nullable = true
+		return schema, nil
+	case strings.HasPrefix(goType, "[]"):
+		items, err := types.resolve(goType[2:], components)
+		if err != nil {
+			return openapi.Schema{}, err
+		}
+		return openapi.Schema{Type: "array", Items: &items}, nil
+	case strings.HasPrefix(goType, "map[string]"):
+		values, err := types.resolve(goType[len("map[string]"):], components)
+		if err != nil {
+			return openapi.Schema{}, err
+		}
+		return openapi.Schema{Type: "object", AdditionalProperties: &values}, nil
+	}
+	return openapi.Schema{}, fmt.Errorf("%w: %s, map it with GoType=type[:format]", ErrUnsupportedSchemaType, goType)
+}
+
+type OpenAPI struct {
+	title      string
+	handlers   []Handler
+	structs    map[string]file.Struct
+	types      SchemaTypes
+	components []openapi.Component
+}
+
+// NewOpenAPI describes the CRUD routes of every resource. structs holds the
+// declarations of the entity package, used to derive the schemas of the
+// resources and of the entities they reference.
+func NewOpenAPI(title string, basePkg string, resources []EntityName, structs []file.Struct, types SchemaTypes) (OpenAPI, error) {
+	o := OpenAPI{
+		title:   title,
+		structs: make(map[string]file.Struct, len(structs)),
+		types:   types,
+	}
+	for _, s := range structs {
+		o.structs[s.Name] = s
+	}
+
+	pending := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		if _, ok := o.structs[resource.PascalCase()]; !ok {
+			return OpenAPI{}, fmt.Errorf("%w: %s", ErrStructNotFound, resource.PascalCase())
+		}
+		o.handlers = append(o.handlers, NewHandler(resource, basePkg))
+		pending = append(pending, resource.PascalCase())
+	}
+	if err := o.buildComponents(pending); err != nil {
+		return OpenAPI{}, err
+	}
+
+	return o, nil
+}
+
+func (o *OpenAPI) buildComponents(pending []string) error {
+	seen := make(map[string]bool)
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		schema, err := o.structSchema(o.structs[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		o.components = append(o.components, openapi.Component{Name: name, Schema: schema})
+		pending = append(pending, schema.Refs()...)
+	}
+
+	o.components = append(o.components, openapi.Component{
+		Name: ERROR_SCHEMA,
+		Schema: openapi.Schema{
+			Type:       "object",
+			Required:   []string{"error"},
+			Properties: []openapi.Property{{Name: "error", Schema: openapi.Schema{Type: "string"}}},
+		},
+	})
+	return nil
+}
+
+func (o OpenAPI) structSchema(s file.Struct) (openapi.Schema, error) {
+	schema := openapi.Schema{Type: "object"}
+	for _, field := range s.Fields {
+		if field.Type == "" || !isExported(field.Name) || jsonIgnored(field.Tag) {
+			continue
+		}
+		fieldSchema, err := o.types.resolve(field.Type, o.structs)
+		if err != nil {
+			return openapi.Schema{}, err
+		}
+
+		name, omitEmpty := jsonName(field)
+		schema.Properties = append(schema.Properties, openapi.Property{Name: name, Schema: fieldSchema})
+		if !omitEmpty && !strings.HasPrefix(field.Type, "*") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema, nil
+}
+
+// jsonName returns the name encoding/json uses for the field and whether it
+// is omitted when empty.
+func jsonName(field file.Field) (string, bool) {
+	tag, err := file.ParseTag(field.Tag)
+	if err != nil {
+		return field.Name, false
+	}
+	key, _ := tag.Get(JSON_TAG)
+	name := key.Value
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range key.Options {
+		if option == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+func (o OpenAPI) FilePath() string {
+	return OPENAPI_PATH
+}
+
+func (o OpenAPI) Document() openapi.Document {
+	doc := openapi.Document{
+		Title:      o.title,
+		Version:    OPENAPI_VERSION,
+		Components: o.components,
+	}
+	for _, h := range o.handlers {
+		doc.Paths = append(doc.Paths, o.paths(h)...)
+	}
+	return doc
+}
+
+func (o OpenAPI) paths(h Handler) []openapi.Path {
+	paths := make([]openapi.Path, 0)
+	index := make(map[string]int)
+	for _, r := range h.routes() {
+		i, ok := index[r.path]
+		if !ok {
+			i = len(paths)
+			index[r.path] = i
+			paths = append(paths, openapi.Path{Pattern: r.path})
+		}
+		paths[i].Operations = append(paths[i].Operations, o.operation(h, r))
+	}
+	return paths
+}
+
+func (o OpenAPI) operation(h Handler, r route) openapi.Operation {
+	structName := h.service.repository.structName
+	subject := structName
+	if r.name == "list" {
+		subject = structName.Plural()
+	}
+
+	op := openapi.Operation{
+		Method:      r.verb,
+		OperationID: r.name + subject.PascalCase(),
+		Summary:     operationSummary(r, subject),
+		Tags:        []string{structName.PascalCase()},
+	}
+
+	badRequest := ""
+	identified := false
+	for _, param := range r.method.Params {
+		switch param.Type {
+		case CONTEXT_TYPE:
+		case ID_TYPE:
+			idSchema, _ := o.types.resolve(ID_TYPE, o.structs)
+			op.Parameters = append(op.Parameters, openapi.Parameter{
+				Name:     param.Name,
+				In:       "path",
+				Required: true,
+				Schema:   idSchema,
+			})
+			badRequest = "Invalid id"
+			identified = true
+		default:
+			body := o.schemaFor(param.Type, structName)
+			op.RequestBody = &body
+			badRequest = "Invalid " + words(structName)
+		}
+	}
+
+	success := openapi.Response{Status: r.code, Description: successDescription(r, subject)}
+	for _, result := range r.method.Results {
+		if result.Type != "error" {
+			schema := o.schemaFor(result.Type, structName)
+			success.Schema = &schema
+		}
+	}
+	op.Responses = append(op.Responses, success)
+
+	errorSchema := openapi.Ref(ERROR_SCHEMA)
+	if badRequest != "" {
+		op.Responses = append(op.Responses, openapi.Response{Status: 400, Description: badRequest, Schema: &errorSchema})
+	}
+	if identified {
+		op.Responses = append(op.Responses, openapi.Response{
+			Status:      404,
+			Description: capitalize(words(structName)) + " not found",
+			Schema:      &errorSchema,
+		})
+	}
+	op.Responses = append(op.Responses, openapi.Response{Status: 500, Description: "Unexpected error", Schema: &errorSchema})
+	return op
+}
+
+// schemaFor maps the entity types used by the service methods to references
+// to the entity component.
+func (o OpenAPI) schemaFor(goType string, structName EntityName) openapi.Schema {
+	ref := openapi.Ref(structName.PascalCase())
+	if strings.HasPrefix(goType, "[]") {
+		return openapi.Schema{Type: "array", Items: &ref}
+	}
+	return ref
+}
+
+func words(en EntityName) string {
+	return strings.ReplaceAll(en.SnakeCase(), "_", " ")
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func operationSummary(r route, subject EntityName) string {
+	summary := capitalize(r.name) + " " + words(subject)
+	if strings.HasSuffix(r.endpoint.path, "/{id}") {
+		summary += " by id"
+	}
+	return summary
+}
+
+func successDescription(r route, subject EntityName) string {
+	switch r.name {
+	case "list":
+		return "The " + words(subject)
+	case "create":
+		return "The created " + words(subject)
+	case "update":
+		return "The updated " + words(subject)
+	case "delete":
+		return capitalize(words(subject)) + " deleted"
+	}
+	return "The " + words(subject)
+}
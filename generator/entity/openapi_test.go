@@ -0,0 +1,178 @@
+package entity_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/openapi"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func openAPIStructs() []file.Struct {
+	return []file.Struct{
+		{
+			Name: "Address",
+			Fields: []file.Field{
+				{Name: "Street", Type: "string", Tag: "`json:\"street\"`"},
+			},
+		},
+		{
+			Name: "Unused",
+			Fields: []file.Field{
+				{Name: "Price", Type: "decimal.Decimal"},
+			},
+		},
+		{
+			Name: "XptoStructName",
+			Fields: []file.Field{
+				{Name: "ID", Type: "uuid.UUID", Tag: "`json:\"id\"`"},
+				{Name: "Name", Type: "string"},
+				{Name: "Nickname", Type: "*string", Tag: "`json:\"nickname\"`"},
+				{Name: "Tags", Type: "[]string", Tag: "`json:\"tags,omitempty\"`"},
+				{Name: "Address", Type: "Address", Tag: "`json:\"address\"`"},
+				{Name: "Secret", Type: "string", Tag: "`json:\"-\"`"},
+				{Name: "internal", Type: "string"},
+			},
+		},
+	}
+}
+
+func TestNewOpenAPI(t *testing.T) {
+	structName := entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice")
+	resources := []entity.EntityName{structName}
+
+	t.Run("it should derive component schemas from the struct fields", func(t *testing.T) {
+		spec, err := entity.NewOpenAPI("microservice", "github.com/eduardoths/microservice", resources, openAPIStructs(), entity.DefaultSchemaTypes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := spec.Document().Components
+		want := []openapi.Component{
+			{
+				Name: "XptoStructName",
+				Schema: openapi.Schema{
+					Type:     "object",
+					Required: []string{"id", "Name", "address"},
+					Properties: []openapi.Property{
+						{Name: "id", Schema: openapi.Schema{Type: "string", Format: "uuid"}},
+						{Name: "Name", Schema: openapi.Schema{Type: "string"}},
+						{Name: "nickname", Schema: openapi.Schema{Type: "string", Nullable: true}},
+						{Name: "tags", Schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}},
+						{Name: "address", Schema: openapi.Ref("Address")},
+					},
+				},
+			},
+			{
+				Name: "Address",
+				Schema: openapi.Schema{
+					Type:       "object",
+					Required:   []string{"street"},
+					Properties: []openapi.Property{{Name: "street", Schema: openapi.Schema{Type: "string"}}},
+				},
+			},
+			{
+				Name: "Error",
+				Schema: openapi.Schema{
+					Type:       "object",
+					Required:   []string{"error"},
+					Properties: []openapi.Property{{Name: "error", Schema: openapi.Schema{Type: "string"}}},
+				},
+			},
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should describe the handler routes", func(t *testing.T) {
+		spec, err := entity.NewOpenAPI("microservice", "github.com/eduardoths/microservice", resources, openAPIStructs(), entity.DefaultSchemaTypes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		doc := spec.Document()
+		if want := "openapi.yaml"; want != spec.FilePath() {
+			utils.Error(t, want, spec.FilePath())
+		}
+
+		actual := make([]string, 0)
+		for _, path := range doc.Paths {
+			for _, op := range path.Operations {
+				statuses := make([]string, 0, len(op.Responses))
+				for _, response := range op.Responses {
+					statuses = append(statuses, strconv.Itoa(response.Status))
+				}
+				actual = append(actual, op.Method+" "+path.Pattern+" "+op.OperationID+" "+strings.Join(statuses, ","))
+			}
+		}
+		want := []string{
+			"GET /xpto-struct-names listXptoStructNames 200,500",
+			"POST /xpto-struct-names createXptoStructName 201,400,500",
+			"GET /xpto-struct-names/{id} getXptoStructName 200,400,404,500",
+			"PUT /xpto-struct-names/{id} updateXptoStructName 200,400,404,500",
+			"DELETE /xpto-struct-names/{id} deleteXptoStructName 204,400,404,500",
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+
+		wantParts := []string{
+			"    get:\n" +
+				"      operationId: listXptoStructNames\n" +
+				"      summary: List xpto struct names\n",
+			"              schema:\n" +
+				"                type: array\n" +
+				"                items:\n" +
+				"                  $ref: \"#/components/schemas/XptoStructName\"\n",
+			"        \"404\":\n" +
+				"          description: Xpto struct name not found\n",
+		}
+		rendered := doc.String()
+		for _, part := range wantParts {
+			if !strings.Contains(rendered, part) {
+				utils.Error(t, part, rendered)
+			}
+		}
+	})
+
+	t.Run("it should fail for unmapped types", func(t *testing.T) {
+		structs := []file.Struct{{Name: "XptoStructName", Fields: []file.Field{{Name: "Price", Type: "decimal.Decimal"}}}}
+		_, err := entity.NewOpenAPI("microservice", "github.com/eduardoths/microservice", resources, structs, entity.DefaultSchemaTypes())
+		if !errors.Is(err, entity.ErrUnsupportedSchemaType) {
+			utils.Error(t, entity.ErrUnsupportedSchemaType, err)
+		}
+	})
+
+	t.Run("it should fail when the resource struct is missing", func(t *testing.T) {
+		_, err := entity.NewOpenAPI("microservice", "github.com/eduardoths/microservice", resources, nil, entity.DefaultSchemaTypes())
+		if !errors.Is(err, entity.ErrStructNotFound) {
+			utils.Error(t, entity.ErrStructNotFound, err)
+		}
+	})
+}
+
+func TestParseSchemaType(t *testing.T) {
+	t.Run("it should parse the type and format", func(t *testing.T) {
+		goType, schema, err := entity.ParseSchemaType("decimal.Decimal=string:decimal")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := openapi.Schema{Type: "string", Format: "decimal"}
+		if goType != "decimal.Decimal" || !reflect.DeepEqual(want, schema) {
+			utils.Error(t, want, schema)
+		}
+	})
+
+	t.Run("it should fail without a type", func(t *testing.T) {
+		_, _, err := entity.ParseSchemaType("decimal.Decimal")
+		if !errors.Is(err, entity.ErrInvalidSchemaType) {
+			utils.Error(t, entity.ErrInvalidSchemaType, err)
+		}
+	})
+}
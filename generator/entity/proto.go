@@ -0,0 +1,444 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/proto"
+	"github.com/eduardoths/micro-cli/utils"
+)
+
+type Proto struct {
+	protoName  EntityName
+	serverName EntityName
+	service    Service
+	types      ProtoTypes
+	fields     []protoEntityField
+}
+
+type protoEntityField struct {
+	goName    string
+	protoName string
+	pbName    string
+	goType    string
+	protoFieldType
+}
+
+// rpcPrefixes maps each repository method to the prefix of the rpc exposing it.
+var rpcPrefixes = map[string]string{
+	"GetAll": "List",
+	"Get":    "Get",
+	"Create": "Create",
+	"Update": "Update",
+	"Delete": "Delete",
+}
+
+const (
+	EMPTY_PROTO_TYPE   = "google.protobuf.Empty"
+	EMPTY_PROTO_IMPORT = "google/protobuf/empty.proto"
+	EMPTYPB_PKG        = "google.golang.org/protobuf/types/known/emptypb"
+	GRPC_CODES_PKG     = "google.golang.org/grpc/codes"
+	GRPC_STATUS_PKG    = "google.golang.org/grpc/status"
+)
+
+func NewProto(structName EntityName, basePkg string, entity file.Struct, types ProtoTypes) (Proto, error) {
+	p := Proto{
+		protoName: NewEntityName(
+			structName.PascalCase(),
+			utils.MergePaths(PROTO_PATH, structName.SnakeCase()),
+			basePkg,
+		),
+		serverName: NewEntityName(
+			structName.PascalCase()+"Server",
+			utils.MergePaths(GRPC_PATH, structName.SnakeCase()),
+			basePkg,
+		),
+		service: NewService(structName, basePkg),
+		types:   types,
+	}
+
+	for _, field := range entity.Fields {
+		if field.Type == "" || !isExported(field.Name) || jsonIgnored(field.Tag) {
+			continue
+		}
+		fieldType, err := types.resolve(field.Type)
+		if err != nil {
+			return Proto{}, err
+		}
+		protoName := utils.ToSnakeCase(field.Name)
+		p.fields = append(p.fields, protoEntityField{
+			goName:         field.Name,
+			protoName:      protoName,
+			pbName:         NewEntityName(protoName, "", "").PascalCase(),
+			goType:         field.Type,
+			protoFieldType: fieldType,
+		})
+	}
+	if _, err := types.resolve(ID_TYPE); err != nil {
+		return Proto{}, err
+	}
+
+	return p, nil
+}
+
+func isExported(name string) bool {
+	return name != "" && strings.ToUpper(name[:1]) == name[:1]
+}
+
+func jsonIgnored(tag string) bool {
+	parsed, err := file.ParseTag(tag)
+	if err != nil {
+		return false
+	}
+	key, ok := parsed.Get(JSON_TAG)
+	return ok && key.Value == "-" && len(key.Options) == 0
+}
+
+func (p Proto) structName() EntityName {
+	return p.service.repository.structName
+}
+
+func (p Proto) messageName() string {
+	return p.structName().PascalCase()
+}
+
+func (p Proto) serviceName() string {
+	return p.structName().PascalCase() + "Service"
+}
+
+func (p Proto) pbImport() file.Import {
+	return file.Import{
+		Path: p.protoName.FileImport().Path,
+		Name: p.protoName.ImportName() + "pb",
+	}
+}
+
+func (p Proto) pbType(message string) string {
+	return p.pbImport().Name + "." + message
+}
+
+func (p Proto) ProtoFilePath() string {
+	return utils.MergePaths(PROTO_PATH, p.structName().SnakeCase(), p.structName().SnakeCase()+".proto")
+}
+
+func (p Proto) FilePath() string {
+	return p.serverName.FilePath()
+}
+
+type rpc struct {
+	proto.RPC
+	method   file.Method
+	request  proto.Message
+	response *proto.Message
+}
+
+func (p Proto) rpcs() []rpc {
+	idType, _ := p.types.resolve(ID_TYPE)
+	rpcs := make([]rpc, 0, len(rpcPrefixes))
+	for _, m := range p.service.Interface.Methods {
+		prefix, ok := rpcPrefixes[m.Name]
+		if !ok {
+			continue
+		}
+		subject := p.structName().PascalCase()
+		if m.Name == "GetAll" {
+			subject = p.structName().Plural().PascalCase()
+		}
+
+		r := rpc{method: m}
+		r.Name = prefix + subject
+		r.Request = r.Name + "Request"
+		r.request = proto.Message{Name: r.Request}
+		for _, param := range m.Params {
+			switch param.Type {
+			case CONTEXT_TYPE:
+			case ID_TYPE:
+				r.request.Fields = append(r.request.Fields, proto.Field{
+					Name: "id", Type: idType.Name, Number: len(r.request.Fields) + 1,
+				})
+			default:
+				r.request.Fields = append(r.request.Fields, proto.Field{
+					Name: p.structName().SnakeCase(), Type: p.messageName(), Number: len(r.request.Fields) + 1,
+				})
+			}
+		}
+
+		r.Response = EMPTY_PROTO_TYPE
+		for _, result := range m.Results {
+			switch {
+			case result.Type == "error":
+			case strings.HasPrefix(result.Type, "[]"):
+				r.Response = r.Name + "Response"
+				r.response = &proto.Message{
+					Name: r.Response,
+					Fields: []proto.Field{
+						{Name: p.structName().Plural().SnakeCase(), Type: p.messageName(), Number: 1, Repeated: true},
+					},
+				}
+			default:
+				r.Response = p.messageName()
+			}
+		}
+		rpcs = append(rpcs, r)
+	}
+	return rpcs
+}
+
+func (p Proto) ProtoFile() proto.File {
+	f := proto.File{
+		Package: p.structName().SnakeCase(),
+		Options: []proto.Option{
+			{Name: "go_package", Value: p.pbImport().Path + ";" + p.pbImport().Name},
+		},
+		Services: []proto.Service{{Name: p.serviceName()}},
+	}
+
+	entity := proto.Message{Name: p.messageName()}
+	for i, field := range p.fields {
+		entity.Fields = append(entity.Fields, proto.Field{
+			Name:     field.protoName,
+			Type:     field.Name,
+			Number:   i + 1,
+			Repeated: field.repeated,
+			Optional: field.optional,
+		})
+		if field.Import != "" {
+			f.Imports = append(f.Imports, field.Import)
+		}
+	}
+	f.Messages = append(f.Messages, entity)
+
+	for _, r := range p.rpcs() {
+		f.Services[0].RPCs = append(f.Services[0].RPCs, r.RPC)
+		f.Messages = append(f.Messages, r.request)
+		if r.response != nil {
+			f.Messages = append(f.Messages, *r.response)
+		}
+		if r.Response == EMPTY_PROTO_TYPE {
+			f.Imports = append(f.Imports, EMPTY_PROTO_IMPORT)
+		}
+	}
+	return f
+}
+
+func (p Proto) File() file.File {
+	name := p.serverName.PascalCase()
+	server := file.Struct{
+		Name: name,
+		Fields: []file.Field{
+			{Name: p.pbType("Unimplemented" + p.serviceName() + "Server")},
+			{Name: "service", Type: p.service.serviceName.Type()},
+		},
+		Implementations: []file.Implementation{
+			{
+				Func: file.Method{
+					Name:    "New" + name,
+					Params:  file.Args{{Name: "service", Type: p.service.serviceName.Type()}},
+					Results: file.Args{{Type: "*" + name}},
+				},
+				CodeLines: []string{"return &" + name + "{service: service}"},
+			},
+		},
+	}
+
+	imports := file.Imports{
+		{Path: CONTEXT_PKG},
+		{Path: ERRORS_PKG},
+		{Path: GRPC_CODES_PKG},
+		{Path: GRPC_STATUS_PKG},
+		p.pbImport(),
+		p.service.serviceName.FileImport(),
+		p.structName().FileImport(),
+	}
+	for _, r := range p.rpcs() {
+		server.Implementations = append(server.Implementations, p.rpcImplementation(r))
+		if r.Response == EMPTY_PROTO_TYPE {
+			imports = append(imports, file.Import{Path: EMPTYPB_PKG})
+		}
+		for _, param := range r.method.Params {
+			if idType, _ := p.types.resolve(ID_TYPE); param.Type == ID_TYPE && idType.GoImport.Path != "" {
+				imports = append(imports, idType.GoImport)
+			}
+		}
+	}
+	server.Implementations = append(server.Implementations, p.converters()...)
+	for _, field := range p.fields {
+		if field.GoImport.Path != "" {
+			imports = append(imports, field.GoImport)
+		}
+	}
+
+	return file.File{
+		Package: p.serverName.ImportName(),
+		Imports: imports,
+		Vars: []file.Var{
+			{Name: "_", Type: p.pbType(p.serviceName() + "Server"), Value: "(*" + name + ")(nil)"},
+		},
+		Structs: []file.Struct{server},
+	}
+}
+
+func (p Proto) rpcImplementation(r rpc) file.Implementation {
+	idType, _ := p.types.resolve(ID_TYPE)
+	lines := make([]string, 0)
+	args := make([]string, 0, len(r.method.Params))
+	invalidArgument := []string{
+		"if err != nil {",
+		"\treturn nil, status.Error(codes.InvalidArgument, err.Error())",
+		"}",
+	}
+
+	for _, param := range r.method.Params {
+		switch param.Type {
+		case CONTEXT_TYPE:
+			args = append(args, "ctx")
+		case ID_TYPE:
+			value := idType.fromProto("req.GetId()")
+			if idType.Fallible {
+				lines = append(lines, param.Name+", err := "+value)
+				lines = append(lines, invalidArgument...)
+			} else {
+				lines = append(lines, param.Name+" := "+value)
+			}
+			args = append(args, param.Name)
+		default:
+			getter := "req.Get" + NewEntityName(p.structName().SnakeCase(), "", "").PascalCase() + "()"
+			lines = append(lines, param.Name+", err := "+p.fromProtoFunc()+"("+getter+")")
+			lines = append(lines, invalidArgument...)
+			args = append(args, param.Name)
+		}
+	}
+
+	call := p.serverName.Alias() + ".service." + r.method.Name + "(" + strings.Join(args, ", ") + ")"
+	result := responseResult(r.method)
+	responseType := "*" + p.pbType(r.Response)
+	switch {
+	case result == "":
+		responseType = "*emptypb.Empty"
+		lines = append(lines,
+			"if err := "+call+"; err != nil {",
+			"\treturn nil, toStatus(err)",
+			"}",
+			"return &emptypb.Empty{}, nil",
+		)
+	case r.response != nil:
+		listField := NewEntityName(r.response.Fields[0].Name, "", "").PascalCase()
+		lines = append(lines,
+			result+", err := "+call,
+			"if err != nil {",
+			"\treturn nil, toStatus(err)",
+			"}",
+			"res := &"+p.pbType(r.Response)+"{"+listField+": make([]*"+p.pbType(p.messageName())+", 0, len("+result+"))}",
+			"for _, item := range "+result+" {",
+			"\tres."+listField+" = append(res."+listField+", "+p.toProtoFunc()+"(item))",
+			"}",
+			"return res, nil",
+		)
+	default:
+		lines = append(lines,
+			result+", err := "+call,
+			"if err != nil {",
+			"\treturn nil, toStatus(err)",
+			"}",
+			"return "+p.toProtoFunc()+"("+result+"), nil",
+		)
+	}
+
+	return file.Implementation{
+		StructAlias: p.serverName.Alias(),
+		StructName:  "*" + p.serverName.PascalCase(),
+		Func: file.Method{
+			Name: r.Name,
+			Params: file.Args{
+				{Name: "ctx", Type: CONTEXT_TYPE},
+				{Name: "req", Type: "*" + p.pbType(r.Request)},
+			},
+			Results: file.Args{{Type: responseType}, {Type: "error"}},
+		},
+		CodeLines: lines,
+	}
+}
+
+func (p Proto) toProtoFunc() string {
+	return p.structName().CamelCase() + "ToProto"
+}
+
+func (p Proto) fromProtoFunc() string {
+	return p.structName().CamelCase() + "FromProto"
+}
+
+// converters returns the free functions of the server file: entity to
+// message conversions and the error to gRPC status mapping.
+func (p Proto) converters() []file.Implementation {
+	codes := file.Import{Path: GRPC_CODES_PKG}
+	statusError := file.Qual(file.Import{Path: GRPC_STATUS_PKG}, "Error")
+	value := p.structName().CamelCase()
+	toProto := []string{"return &" + p.pbType(p.messageName()) + "{"}
+	fromProto := make([]string, 0, len(p.fields)+1)
+	for _, field := range p.fields {
+		toProto = append(toProto, "\t"+field.pbName+": "+field.toProto(value+"."+field.goName)+",")
+
+		source := "m.Get" + field.pbName + "()"
+		if field.optional {
+			source = "m." + field.pbName
+		}
+		target := value + "." + field.goName
+		switch {
+		case field.Fallible && field.Name == "string":
+			fromProto = append(fromProto,
+				"if v := "+source+"; v != \"\" {",
+				"\tif "+target+", err = "+field.fromProto("v")+"; err != nil {",
+				"\t\treturn "+value+", err",
+				"\t}",
+				"}",
+			)
+		case field.Fallible:
+			fromProto = append(fromProto,
+				"if "+target+", err = "+field.fromProto(source)+"; err != nil {",
+				"\treturn "+value+", err",
+				"}",
+			)
+		default:
+			fromProto = append(fromProto, target+" = "+field.fromProto(source))
+		}
+	}
+	toProto = append(toProto, "}")
+	fromProto = append(fromProto, "return "+value+", nil")
+
+	return []file.Implementation{
+		{
+			Func: file.Method{
+				Name:    p.toProtoFunc(),
+				Params:  file.Args{{Name: value, Type: p.structName().Type()}},
+				Results: file.Args{{Type: "*" + p.pbType(p.messageName())}},
+			},
+			CodeLines: toProto,
+		},
+		{
+			Func: file.Method{
+				Name:   p.fromProtoFunc(),
+				Params: file.Args{{Name: "m", Type: "*" + p.pbType(p.messageName())}},
+				Results: file.Args{
+					{Name: value, Type: p.structName().Type()},
+					{Name: "err", Type: "error"},
+				},
+			},
+			CodeLines: fromProto,
+		},
+		{
+			Func: file.Method{
+				Name:    "toStatus",
+				Params:  file.Args{{Name: "err", Type: "error"}},
+				Results: file.Args{{Type: "error"}},
+			},
+			Body: file.Block{
+				file.If(
+					file.Qual(file.Import{Path: ERRORS_PKG}, "Is").Call(
+						file.Code("err"), file.Code(p.service.serviceName.ImportName()+".ErrNotFound")),
+					file.Return(statusError.Call(file.Qual(codes, "NotFound"), file.Code("err.Error()"))),
+				),
+				file.Return(statusError.Call(file.Qual(codes, "Internal"), file.Code("err.Error()"))),
+			},
+		},
+	}
+}
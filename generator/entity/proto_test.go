@@ -0,0 +1,252 @@
+package entity_test
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func xptoStructFields() file.Struct {
+	return file.Struct{
+		Name: "XptoStructName",
+		Fields: []file.Field{
+			{Name: "Base"},
+			{Name: "ID", Type: "uuid.UUID", Tag: "`json:\"id\"`"},
+			{Name: "Name", Type: "string"},
+			{Name: "Nickname", Type: "*string"},
+			{Name: "Tags", Type: "[]string"},
+			{Name: "Count", Type: "int"},
+			{Name: "Secret", Type: "string", Tag: "`json:\"-\"`"},
+			{Name: "internal", Type: "string"},
+			{Name: "CreatedAt", Type: "time.Time"},
+		},
+	}
+}
+
+func TestNewProto(t *testing.T) {
+	structName := entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice")
+
+	t.Run("it should return the file paths", func(t *testing.T) {
+		p, err := entity.NewProto(structName, "github.com/eduardoths/microservice", xptoStructFields(), entity.DefaultProtoTypes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "proto/xpto_struct_name/xpto_struct_name.proto"; want != p.ProtoFilePath() {
+			utils.Error(t, want, p.ProtoFilePath())
+		}
+		if want := "src/grpc/xpto_struct_name/xpto_struct_name_server.go"; want != p.FilePath() {
+			utils.Error(t, want, p.FilePath())
+		}
+	})
+
+	t.Run("it should return a valid proto file", func(t *testing.T) {
+		p, err := entity.NewProto(structName, "github.com/eduardoths/microservice", xptoStructFields(), entity.DefaultProtoTypes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := p.ProtoFile().String()
+		want := "syntax = \"proto3\";\n\n" +
+			"package xpto_struct_name;\n\n" +
+			"option go_package = \"github.com/eduardoths/microservice/proto/xpto_struct_name;xptostructnamepb\";\n\n" +
+			"import \"google/protobuf/empty.proto\";\n" +
+			"import \"google/protobuf/timestamp.proto\";\n\n" +
+			"service XptoStructNameService {\n" +
+			"  rpc ListXptoStructNames(ListXptoStructNamesRequest) returns (ListXptoStructNamesResponse);\n" +
+			"  rpc GetXptoStructName(GetXptoStructNameRequest) returns (XptoStructName);\n" +
+			"  rpc CreateXptoStructName(CreateXptoStructNameRequest) returns (XptoStructName);\n" +
+			"  rpc UpdateXptoStructName(UpdateXptoStructNameRequest) returns (XptoStructName);\n" +
+			"  rpc DeleteXptoStructName(DeleteXptoStructNameRequest) returns (google.protobuf.Empty);\n" +
+			"}\n\n" +
+			"message XptoStructName {\n" +
+			"  string id = 1;\n" +
+			"  string name = 2;\n" +
+			"  optional string nickname = 3;\n" +
+			"  repeated string tags = 4;\n" +
+			"  int64 count = 5;\n" +
+			"  google.protobuf.Timestamp created_at = 6;\n" +
+			"}\n\n" +
+			"message ListXptoStructNamesRequest {}\n\n" +
+			"message ListXptoStructNamesResponse {\n" +
+			"  repeated XptoStructName xpto_struct_names = 1;\n" +
+			"}\n\n" +
+			"message GetXptoStructNameRequest {\n" +
+			"  string id = 1;\n" +
+			"}\n\n" +
+			"message CreateXptoStructNameRequest {\n" +
+			"  XptoStructName xpto_struct_name = 1;\n" +
+			"}\n\n" +
+			"message UpdateXptoStructNameRequest {\n" +
+			"  string id = 1;\n" +
+			"  XptoStructName xpto_struct_name = 2;\n" +
+			"}\n\n" +
+			"message DeleteXptoStructNameRequest {\n" +
+			"  string id = 1;\n" +
+			"}\n"
+		if want != actual {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should return a server adapter delegating to the service", func(t *testing.T) {
+		p, err := entity.NewProto(structName, "github.com/eduardoths/microservice", xptoStructFields(), entity.DefaultProtoTypes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := p.File().String()
+		wantParts := []string{
+			"\txptostructnamepb \"github.com/eduardoths/microservice/proto/xpto_struct_name\"\n",
+			"\t\"google.golang.org/protobuf/types/known/timestamppb\"\n",
+			"var _ xptostructnamepb.XptoStructNameServiceServer = (*XptoStructNameServer)(nil)\n",
+			"type XptoStructNameServer struct {\n" +
+				"\txptostructnamepb.UnimplementedXptoStructNameServiceServer\n" +
+				"\tservice xptostructname.XptoStructNameService\n" +
+				"}\n",
+			"func (xsns *XptoStructNameServer) DeleteXptoStructName(ctx context.Context, req *xptostructnamepb.DeleteXptoStructNameRequest) (*emptypb.Empty, error) {\n",
+			"\tupdated, err := xsns.service.Update(ctx, id, xptoStructName)\n",
+			"\t\tCreatedAt: timestamppb.New(xptoStructName.CreatedAt),\n",
+			"\tif v := m.GetId(); v != \"\" {\n" +
+				"\t\tif xptoStructName.ID, err = uuid.Parse(v); err != nil {\n",
+			"\txptoStructName.Nickname = m.Nickname\n",
+			"\txptoStructName.Count = int(m.GetCount())\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+		if strings.Contains(actual, "Secret") || strings.Contains(actual, "internal") {
+			utils.Error(t, "ignored and unexported fields to be skipped", actual)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "", actual, parser.AllErrors); err != nil {
+			utils.Error(t, "a valid go file", err)
+		}
+	})
+
+	t.Run("it should fail for unmapped types", func(t *testing.T) {
+		fields := file.Struct{Fields: []file.Field{{Name: "Price", Type: "decimal.Decimal"}}}
+		_, err := entity.NewProto(structName, "github.com/eduardoths/microservice", fields, entity.DefaultProtoTypes())
+		if !errors.Is(err, entity.ErrUnsupportedProtoType) {
+			utils.Error(t, entity.ErrUnsupportedProtoType, err)
+		}
+	})
+
+	t.Run("it should keep the fields named - in json", func(t *testing.T) {
+		fields := file.Struct{Fields: []file.Field{
+			{Name: "Dash", Type: "string", Tag: "`json:\"-,\"`"},
+			{Name: "Secret", Type: "string", Tag: "`db:\"secret\" json:\"-\"`"},
+		}}
+		p, err := entity.NewProto(structName, "github.com/eduardoths/microservice", fields, entity.DefaultProtoTypes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := p.ProtoFile().String()
+		if !strings.Contains(actual, "  string dash = 1;\n") {
+			utils.Error(t, "  string dash = 1;\n", actual)
+		}
+		if strings.Contains(actual, "secret") {
+			utils.Error(t, "the ignored field to be skipped", actual)
+		}
+	})
+
+	t.Run("it should use custom type mappings", func(t *testing.T) {
+		types := entity.DefaultProtoTypes()
+		goType, protoType, err := entity.ParseProtoType("decimal.Decimal=string;%s.String();decimal.RequireFromString(%s)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		types[goType] = protoType
+		fields := file.Struct{Fields: []file.Field{{Name: "Price", Type: "decimal.Decimal"}}}
+
+		p, err := entity.NewProto(structName, "github.com/eduardoths/microservice", fields, types)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := p.File().String()
+		for _, want := range []string{
+			"\t\tPrice: xptoStructName.Price.String(),\n",
+			"\txptoStructName.Price = decimal.RequireFromString(m.GetPrice())\n",
+		} {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+	})
+}
+
+func TestParseProtoType(t *testing.T) {
+	type testCase struct {
+		it         string
+		in         string
+		wantGoType string
+		want       entity.ProtoType
+		wantErr    bool
+	}
+
+	tc := []testCase{
+		{
+			it:         "should parse an identity mapping",
+			in:         "customString=string",
+			wantGoType: "customString",
+			want:       entity.ProtoType{Name: "string"},
+		},
+		{
+			it:         "should resolve well known imports",
+			in:         "null.Time=google.protobuf.Timestamp;timestamppb.New(%s.Time);null.TimeFrom(%s.AsTime())",
+			wantGoType: "null.Time",
+			want: entity.ProtoType{
+				Name:      "google.protobuf.Timestamp",
+				Import:    "google/protobuf/timestamp.proto",
+				ToProto:   "timestamppb.New(%s.Time)",
+				FromProto: "null.TimeFrom(%s.AsTime())",
+			},
+		},
+		{
+			it:         "should parse fallible conversions",
+			in:         "decimal.Decimal=string;%s.String();decimal.NewFromString(%s);fallible",
+			wantGoType: "decimal.Decimal",
+			want: entity.ProtoType{
+				Name:      "string",
+				ToProto:   "%s.String()",
+				FromProto: "decimal.NewFromString(%s)",
+				Fallible:  true,
+			},
+		},
+		{
+			it:      "should fail without a proto type",
+			in:      "decimal.Decimal=",
+			wantErr: true,
+		},
+		{
+			it:      "should fail with only one conversion",
+			in:      "decimal.Decimal=string;%s.String()",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			goType, actual, err := entity.ParseProtoType(c.in)
+			if c.wantErr {
+				if !errors.Is(err, entity.ErrInvalidProtoType) {
+					utils.Error(t, entity.ErrInvalidProtoType, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.wantGoType != goType || !reflect.DeepEqual(c.want, actual) {
+				utils.Error(t, c.want, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+var (
+	ErrUnsupportedProtoType = errors.New("unsupported protobuf type")
+	ErrInvalidProtoType     = errors.New("invalid protobuf type mapping")
+)
+
+// ProtoType describes how a Go type is represented in protobuf. ToProto and
+// FromProto are fmt templates converting the value given as %s; an empty
+// template means the value is used as is. When Fallible is set FromProto
+// returns (value, error).
+type ProtoType struct {
+	Name      string
+	Import    string
+	GoImport  file.Import
+	ToProto   string
+	FromProto string
+	Fallible  bool
+}
+
+func (pt ProtoType) identity() bool {
+	return pt.ToProto == "" && pt.FromProto == ""
+}
+
+func (pt ProtoType) toProto(value string) string {
+	if pt.ToProto == "" {
+		return value
+	}
+	return fmt.Sprintf(pt.ToProto, value)
+}
+
+func (pt ProtoType) fromProto(value string) string {
+	if pt.FromProto == "" {
+		return value
+	}
+	return fmt.Sprintf(pt.FromProto, value)
+}
+
+type ProtoTypes map[string]ProtoType
+
+var wellKnownProtoImports = map[string]string{
+	"google.protobuf.Timestamp": "google/protobuf/timestamp.proto",
+	"google.protobuf.Duration":  "google/protobuf/duration.proto",
+	"google.protobuf.Struct":    "google/protobuf/struct.proto",
+	"google.protobuf.Any":       "google/protobuf/any.proto",
+	"google.protobuf.Empty":     "google/protobuf/empty.proto",
+}
+
+func DefaultProtoTypes() ProtoTypes {
+	return ProtoTypes{
+		"string":  {Name: "string"},
+		"bool":    {Name: "bool"},
+		"int32":   {Name: "int32"},
+		"int64":   {Name: "int64"},
+		"uint32":  {Name: "uint32"},
+		"uint64":  {Name: "uint64"},
+		"float32": {Name: "float"},
+		"float64": {Name: "double"},
+		"[]byte":  {Name: "bytes"},
+		"int":     {Name: "int64", ToProto: "int64(%s)", FromProto: "int(%s)"},
+		"uint":    {Name: "uint64", ToProto: "uint64(%s)", FromProto: "uint(%s)"},
+		ID_TYPE: {
+			Name:      "string",
+			GoImport:  file.Import{Path: ID_PKG},
+			ToProto:   "%s.String()",
+			FromProto: ID_PARSE_FUNC + "(%s)",
+			Fallible:  true,
+		},
+		"time.Time": {
+			Name:      "google.protobuf.Timestamp",
+			Import:    wellKnownProtoImports["google.protobuf.Timestamp"],
+			GoImport:  file.Import{Path: "google.golang.org/protobuf/types/known/timestamppb"},
+			ToProto:   "timestamppb.New(%s)",
+			FromProto: "%s.AsTime()",
+		},
+		"time.Duration": {
+			Name:      "google.protobuf.Duration",
+			Import:    wellKnownProtoImports["google.protobuf.Duration"],
+			GoImport:  file.Import{Path: "google.golang.org/protobuf/types/known/durationpb"},
+			ToProto:   "durationpb.New(%s)",
+			FromProto: "%s.AsDuration()",
+		},
+	}
+}
+
+// ParseProtoType parses a "GoType=protoType[;toProto;fromProto[;fallible]]"
+// mapping, e.g. "decimal.Decimal=string;%s.String();decimal.NewFromString(%s);fallible".
+func ParseProtoType(raw string) (string, ProtoType, error) {
+	goType, mapping, ok := strings.Cut(raw, "=")
+	parts := strings.Split(mapping, ";")
+	if !ok || goType == "" || parts[0] == "" || len(parts) == 2 || len(parts) > 4 {
+		return "", ProtoType{}, fmt.Errorf("%w %q, expected GoType=protoType[;toProto;fromProto[;fallible]]", ErrInvalidProtoType, raw)
+	}
+
+	pt := ProtoType{Name: parts[0], Import: wellKnownProtoImports[parts[0]]}
+	if len(parts) >= 3 {
+		pt.ToProto, pt.FromProto = parts[1], parts[2]
+	}
+	if len(parts) == 4 {
+		if parts[3] != "fallible" {
+			return "", ProtoType{}, fmt.Errorf("%w %q, unknown flag %q", ErrInvalidProtoType, raw, parts[3])
+		}
+		pt.Fallible = true
+	}
+	return goType, pt, nil
+}
+
+type protoFieldType struct {
+	ProtoType
+	repeated bool
+	optional bool
+}
+
+func (types ProtoTypes) resolve(goType string) (protoFieldType, error) {
+	if pt, ok := types[goType]; ok {
+		return protoFieldType{ProtoType: pt}, nil
+	}
+	if elem := strings.TrimPrefix(goType, "[]"); elem != goType {
+		if pt, ok := types[elem]; ok && pt.identity() {
+			return protoFieldType{ProtoType: pt, repeated: true}, nil
+		}
+	}
+	if elem := strings.TrimPrefix(goType, "*"); elem != goType {
+		if pt, ok := types[elem]; ok && pt.identity() && !strings.Contains(pt.Name, ".") {
+			return protoFieldType{ProtoType: pt, optional: true}, nil
+		}
+	}
+	return protoFieldType{}, fmt.Errorf("%w %s, map it with a custom type mapping", ErrUnsupportedProtoType, goType)
+}
@@ -8,10 +8,13 @@ import (
 type Repository struct {
 	repoName   EntityName
 	structName EntityName
+	config     ImplementationConfig
 	implStruct file.Struct
 
 	Interface file.Interface
 	Imports   file.Imports
+	Vars      []file.Var
+	Types     []file.Type
 }
 
 type imethod struct {
@@ -21,19 +24,30 @@ type imethod struct {
 }
 
 func NewRepository(structName EntityName, basePkg string) Repository {
+	return NewRepositoryWithConfig(structName, basePkg, DefaultImplementationConfig())
+}
+
+func NewRepositoryWithConfig(structName EntityName, basePkg string, config ImplementationConfig) Repository {
 	repo := Repository{
-		repoName: NewEntityName(
-			structName.PascalCase()+"Repository",
-			utils.MergePaths(REPOSITORIES_PATH, structName.SnakeCase()),
-			basePkg,
-		),
+		repoName:   repositoryName(structName, basePkg),
 		structName: structName,
+		config:     config,
 	}
 	repo.build()
 
 	return repo
 }
 
+// repositoryName returns the name of the repository interface of the
+// entity, in its package.
+func repositoryName(structName EntityName, basePkg string) EntityName {
+	return NewEntityName(
+		structName.PascalCase()+"Repository",
+		utils.MergePaths(REPOSITORIES_PATH, structName.SnakeCase()),
+		basePkg,
+	)
+}
+
 func (r *Repository) build() {
 	r.buildInterface()
 	r.buildImports()
@@ -42,11 +56,13 @@ func (r *Repository) build() {
 
 func (r *Repository) File() file.File {
 	return file.File{
-		Package: r.repoName.ImportName(),
-		Imports: r.Imports,
-		Structs: []file.Struct{r.implStruct},
+		Package:    r.repoName.ImportName(),
+		Imports:    r.Imports,
+		Vars:       r.Vars,
+		Types:      r.Types,
+		Interfaces: []file.Interface{r.Interface},
+		Structs:    []file.Struct{r.implStruct},
 	}
-
 }
 
 func (r *Repository) buildInterface() {
@@ -68,27 +84,22 @@ func (r *Repository) buildImports() {
 	for _, imethod := range internalMethods {
 		r.Imports = append(r.Imports, imethod.imports...)
 	}
+	r.Imports = append(r.Imports, r.implementation().imports()...)
+	r.Imports = append(r.Imports, file.Import{Path: ERRORS_PKG})
 }
 
 func (r *Repository) buildImplementation() {
-	getAll := r.getAllMethod()
-	get := r.getMethod()
-	r.implStruct = file.Struct{
-		Name: r.repoName.PascalCase(),
-		Implementations: []file.Implementation{
-			{
-				StructAlias: r.repoName.Alias(),
-				StructName:  r.repoName.PascalCase(),
-				Func:        getAll.method,
-				CodeLines:   getAll.implementation,
-			},
-			{
-				StructAlias: r.repoName.Alias(),
-				StructName:  r.repoName.PascalCase(),
-				Func:        get.method,
-				CodeLines:   get.implementation,
-			},
-		},
+	impl := r.implementation()
+	r.implStruct = impl.build()
+	r.Types = impl.types()
+	r.Vars = append(impl.vars(), notFoundVar(r.structName))
+}
+
+func (r Repository) implementation() implementation {
+	return implementation{
+		iface:   r.repoName,
+		config:  r.config,
+		methods: r.internalMethods(),
 	}
 }
 
@@ -96,6 +107,9 @@ func (r Repository) internalMethods() []imethod {
 	return []imethod{
 		r.getAllMethod(),
 		r.getMethod(),
+		r.createMethod(),
+		r.updateMethod(),
+		r.deleteMethod(),
 	}
 }
 
@@ -124,6 +138,7 @@ func (r Repository) getAllMethod() imethod {
 			{Path: CONTEXT_PKG},
 			r.structName.FileImport(),
 		},
+		implementation: notImplemented(),
 	}
 }
 
@@ -157,5 +172,122 @@ func (r Repository) getMethod() imethod {
 			{Path: ID_PKG},
 			r.structName.FileImport(),
 		},
+		implementation: notImplemented(),
 	}
 }
+
+func (r Repository) createMethod() imethod {
+	return imethod{
+		method: file.Method{
+			Name: "Create",
+			Params: file.Args{
+				{
+					Name: "ctx",
+					Type: CONTEXT_TYPE,
+				},
+				{
+					Name: r.structName.CamelCase(),
+					Type: r.structName.Type(),
+				},
+			},
+			Results: file.Args{
+				{
+					Name: "created",
+					Type: r.structName.Type(),
+				},
+				{
+					Name: "err",
+					Type: "error",
+				},
+			},
+		},
+		imports: file.Imports{
+			{Path: CONTEXT_PKG},
+			r.structName.FileImport(),
+		},
+		implementation: notImplemented(),
+	}
+}
+
+func (r Repository) updateMethod() imethod {
+	return imethod{
+		method: file.Method{
+			Name: "Update",
+			Params: file.Args{
+				{
+					Name: "ctx",
+					Type: CONTEXT_TYPE,
+				},
+				{
+					Name: "id",
+					Type: ID_TYPE,
+				},
+				{
+					Name: r.structName.CamelCase(),
+					Type: r.structName.Type(),
+				},
+			},
+			Results: file.Args{
+				{
+					Name: "updated",
+					Type: r.structName.Type(),
+				},
+				{
+					Name: "err",
+					Type: "error",
+				},
+			},
+		},
+		imports: file.Imports{
+			{Path: CONTEXT_PKG},
+			{Path: ID_PKG},
+			r.structName.FileImport(),
+		},
+		implementation: notImplemented(),
+	}
+}
+
+func (r Repository) deleteMethod() imethod {
+	return imethod{
+		method: file.Method{
+			Name: "Delete",
+			Params: file.Args{
+				{
+					Name: "ctx",
+					Type: CONTEXT_TYPE,
+				},
+				{
+					Name: "id",
+					Type: ID_TYPE,
+				},
+			},
+			Results: file.Args{
+				{
+					Name: "err",
+					Type: "error",
+				},
+			},
+		},
+		imports: file.Imports{
+			{Path: CONTEXT_PKG},
+			{Path: ID_PKG},
+		},
+		implementation: notImplemented(),
+	}
+}
+
+func notImplemented() []string {
+	return []string{`panic("not implemented")`}
+}
+
+func (r Repository) FilePath() string {
+	return r.repoName.FilePath()
+}
+
+// TestFilePath returns the path of the repository tests earlier versions
+// generated. Repositories come without tests, as there is nothing to assert
+// until their methods are implemented, but the ones generated before are
+// destroyed along with the repository.
+func (r Repository) TestFilePath() string {
+	return testFilePath(r.FilePath())
+}
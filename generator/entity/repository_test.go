@@ -1,6 +1,7 @@
 package entity_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/eduardoths/micro-cli/generator/entity"
@@ -19,6 +20,9 @@ func TestNewRepository(t *testing.T) {
 		want := "\ntype XptoStructNameRepository interface {\n" +
 			"\tGetAll(ctx context.Context) (xptoStructName []structs.XptoStructName, err error)\n" +
 			"\tGet(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error)\n" +
+			"\tCreate(ctx context.Context, xptoStructName structs.XptoStructName) (created structs.XptoStructName, err error)\n" +
+			"\tUpdate(ctx context.Context, id uuid.UUID, xptoStructName structs.XptoStructName) (updated structs.XptoStructName, err error)\n" +
+			"\tDelete(ctx context.Context, id uuid.UUID) (err error)\n" +
 			"}\n"
 		if want != actual.String() {
 			utils.Error(t, want, actual)
@@ -35,6 +39,7 @@ func TestNewRepository(t *testing.T) {
 		want := file.Imports{
 			{Path: "github.com/google/uuid"},
 			{Path: "context"},
+			{Path: "errors"},
 			{Path: "github.com/eduardoths/microservice/src/structs"},
 		}
 		if want.String() != actual.String() {
@@ -52,14 +57,166 @@ func TestNewRepository(t *testing.T) {
 		want := "package xptostructname\n\n" +
 			"import (\n" +
 			"\t\"context\"\n" +
+			"\t\"errors\"\n" +
 			"\t\"github.com/eduardoths/microservice/src/structs\"\n" +
 			"\t\"github.com/google/uuid\"\n" +
 			")\n\n" +
-			"type XptoStructNameRepository struct {}\n\n" +
-			"func (xsnr XptoStructNameRepository) GetAll(ctx context.Context) (xptoStructName []structs.XptoStructName, err error) {\n}\n\n" +
-			"func (xsnr XptoStructNameRepository) Get(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error) {\n}\n"
+			"var _ XptoStructNameRepository = (*xptoStructNameRepository)(nil)\n\n" +
+			"var ErrNotFound = errors.New(\"xpto struct name not found\")\n\n" +
+			"type XptoStructNameRepository interface {\n" +
+			"\tGetAll(ctx context.Context) (xptoStructName []structs.XptoStructName, err error)\n" +
+			"\tGet(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error)\n" +
+			"\tCreate(ctx context.Context, xptoStructName structs.XptoStructName) (created structs.XptoStructName, err error)\n" +
+			"\tUpdate(ctx context.Context, id uuid.UUID, xptoStructName structs.XptoStructName) (updated structs.XptoStructName, err error)\n" +
+			"\tDelete(ctx context.Context, id uuid.UUID) (err error)\n" +
+			"}\n\n" +
+			"type xptoStructNameRepository struct {}\n\n" +
+			"func NewXptoStructNameRepository() XptoStructNameRepository {\n" +
+			"\treturn &xptoStructNameRepository{}\n" +
+			"}\n\n" +
+			"func (xsnr *xptoStructNameRepository) GetAll(ctx context.Context) (xptoStructName []structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameRepository.GetAll\n" +
+			"\tpanic(\"not implemented\")\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsnr *xptoStructNameRepository) Get(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameRepository.Get\n" +
+			"\tpanic(\"not implemented\")\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsnr *xptoStructNameRepository) Create(ctx context.Context, xptoStructName structs.XptoStructName) (created structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameRepository.Create\n" +
+			"\tpanic(\"not implemented\")\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsnr *xptoStructNameRepository) Update(ctx context.Context, id uuid.UUID, xptoStructName structs.XptoStructName) (updated structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameRepository.Update\n" +
+			"\tpanic(\"not implemented\")\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsnr *xptoStructNameRepository) Delete(ctx context.Context, id uuid.UUID) (err error) {\n" +
+			"\t// microcli:begin xptoStructNameRepository.Delete\n" +
+			"\tpanic(\"not implemented\")\n" +
+			"\t// microcli:end\n" +
+			"}\n"
 		if want != actual.String() {
 			utils.Error(t, want, actual)
 		}
 	})
+
+	t.Run("it should inject dependencies through the constructor", func(t *testing.T) {
+		config := entity.DefaultImplementationConfig()
+		config.Dependencies = []entity.Dependency{
+			{Name: "db", Type: "*sql.DB", Import: file.Import{Path: "database/sql"}},
+		}
+		repo := entity.NewRepositoryWithConfig(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := repo.File().String()
+		wantParts := []string{
+			"\t\"database/sql\"\n",
+			"type xptoStructNameRepository struct {\n" +
+				"\tdb *sql.DB\n" +
+				"}\n",
+			"func NewXptoStructNameRepository(db *sql.DB) XptoStructNameRepository {\n" +
+				"\treturn &xptoStructNameRepository{\n" +
+				"\t\tdb: db,\n" +
+				"\t}\n" +
+				"}\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+	})
+
+	t.Run("it should apply the functional options to the address of value implementations", func(t *testing.T) {
+		config := entity.DefaultImplementationConfig()
+		config.PointerReceivers = false
+		config.FunctionalOptions = true
+		config.Dependencies = []entity.Dependency{
+			{Name: "db", Type: "*sql.DB", Import: file.Import{Path: "database/sql"}, Required: true},
+		}
+		repo := entity.NewRepositoryWithConfig(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := repo.File().String()
+		want := "func NewXptoStructNameRepository(db *sql.DB, opts ...XptoStructNameRepositoryOption) XptoStructNameRepository {\n" +
+			"\txsnr := xptoStructNameRepository{\n" +
+			"\t\tdb: db,\n" +
+			"\t}\n" +
+			"\tfor _, opt := range opts {\n" +
+			"\t\topt(&xsnr)\n" +
+			"\t}\n" +
+			"\treturn xsnr\n" +
+			"}\n"
+		if !strings.Contains(actual, want) {
+			utils.Error(t, want, actual)
+		}
+		if strings.Contains(actual, "func WithDb") {
+			utils.Error(t, "no option for required dependencies", actual)
+		}
+	})
+
+	t.Run("it should suffix the receivers named like a dependency", func(t *testing.T) {
+		config := entity.DefaultImplementationConfig()
+		config.Dependencies = []entity.Dependency{{Name: "v", Type: "int"}}
+		repo := entity.NewRepositoryWithConfig(
+			entity.NewEntityName("VA", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := repo.File().String()
+		want := "func (v2 *vARepository) Get(ctx context.Context, id uuid.UUID) (vA structs.VA, err error) {\n"
+		if !strings.Contains(actual, want) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should use value receivers and skip constructor and assertion when configured", func(t *testing.T) {
+		config := entity.ImplementationConfig{}
+		repo := entity.NewRepositoryWithConfig(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := repo.File().String()
+		if strings.Contains(actual, "var _") || strings.Contains(actual, "func NewXptoStructNameRepository") {
+			utils.Error(t, "no constructor nor interface assertion", actual)
+		}
+		want := "func (xsnr xptoStructNameRepository) Get("
+		if !strings.Contains(actual, want) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should assert value implementations", func(t *testing.T) {
+		config := entity.DefaultImplementationConfig()
+		config.PointerReceivers = false
+		repo := entity.NewRepositoryWithConfig(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := repo.File().String()
+		wantParts := []string{
+			"var _ XptoStructNameRepository = xptoStructNameRepository{}\n",
+			"\treturn xptoStructNameRepository{}\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+	})
 }
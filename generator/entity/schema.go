@@ -0,0 +1,238 @@
+package entity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidSchema = errors.New("invalid entity schema")
+
+const (
+	BELONGS_TO_RELATION = "belongs_to"
+	HAS_ONE_RELATION    = "has_one"
+	HAS_MANY_RELATION   = "has_many"
+
+	DEFAULT_ID_FIELD = "ID"
+)
+
+// typeImports holds the import paths of the qualified types a schema may use
+// without declaring their import.
+var typeImports = map[string]string{
+	"uuid": ID_PKG,
+	"time": "time",
+	"json": "encoding/json",
+	"big":  "math/big",
+	"url":  "net/url",
+	"sql":  "database/sql",
+}
+
+// EntitySchema is the declarative description of an entity. It is read from
+// YAML or JSON files, JSON being valid YAML.
+type EntitySchema struct {
+	Name      string           `yaml:"name"`
+	ID        SchemaID         `yaml:"id"`
+	Fields    []SchemaField    `yaml:"fields"`
+	Relations []SchemaRelation `yaml:"relations"`
+}
+
+// SchemaID names and tags the ID field. IDs are always of type ID_TYPE,
+// which the generated repositories, services and handlers identify entities
+// by, so the schema format has no type for it.
+type SchemaID struct {
+	Name string            `yaml:"name"`
+	Tags map[string]string `yaml:"tags"`
+}
+
+type SchemaField struct {
+	Name     string            `yaml:"name"`
+	Type     string            `yaml:"type"`
+	Import   string            `yaml:"import"`
+	Optional bool              `yaml:"optional"`
+	Tags     map[string]string `yaml:"tags"`
+}
+
+// SchemaRelation links the entity to another entity of the same package.
+// belongs_to adds the <Name>ID foreign key, has_one a pointer to the related
+// entity and has_many a slice of them.
+type SchemaRelation struct {
+	Name   string `yaml:"name"`
+	Entity string `yaml:"entity"`
+	Kind   string `yaml:"kind"`
+}
+
+func ParseEntitySchema(data []byte) (EntitySchema, error) {
+	var schema EntitySchema
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&schema); err != nil {
+		return EntitySchema{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if schema.ID.Name == "" {
+		schema.ID.Name = DEFAULT_ID_FIELD
+	}
+	return schema, nil
+}
+
+type Entity struct {
+	structName EntityName
+	schema     EntitySchema
+	tags       TagNaming
+
+	Struct  file.Struct
+	Imports file.Imports
+}
+
+func NewEntity(schema EntitySchema, structsDir string, basePkg string) (Entity, error) {
+	return NewEntityWithTags(schema, structsDir, basePkg, DefaultTagNaming())
+}
+
+// NewEntityWithTags returns the entity of the schema, its fields tagged with
+// the keys of tags besides the ones the schema sets.
+func NewEntityWithTags(schema EntitySchema, structsDir string, basePkg string, tags TagNaming) (Entity, error) {
+	if !isGoName(schema.Name) {
+		return Entity{}, fmt.Errorf("%w: name %q must be an exported Go identifier", ErrInvalidSchema, schema.Name)
+	}
+
+	e := Entity{
+		structName: NewEntityName(schema.Name, structsDir, basePkg),
+		schema:     schema,
+		tags:       tags,
+	}
+	if err := e.build(); err != nil {
+		return Entity{}, err
+	}
+	return e, nil
+}
+
+func (e Entity) Name() EntityName {
+	return e.structName
+}
+
+func (e Entity) FilePath() string {
+	return e.structName.FilePath()
+}
+
+func (e Entity) File() file.File {
+	return file.File{
+		Package: e.structName.ImportName(),
+		Imports: e.Imports,
+		Structs: []file.Struct{e.Struct},
+	}
+}
+
+func (e *Entity) build() error {
+	e.Struct = file.Struct{Name: e.structName.PascalCase()}
+	e.Imports = make(file.Imports, 0)
+
+	fields := make([]SchemaField, 0, len(e.schema.Fields)+len(e.schema.Relations)+1)
+	fields = append(fields, SchemaField{Name: e.schema.ID.Name, Type: ID_TYPE, Tags: e.schema.ID.Tags})
+	fields = append(fields, e.schema.Fields...)
+	for _, relation := range e.schema.Relations {
+		field, err := relation.field()
+		if err != nil {
+			return err
+		}
+		fields = append(fields, field)
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !isGoName(field.Name) {
+			return fmt.Errorf("%w: field name %q must be an exported Go identifier", ErrInvalidSchema, field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("%w: duplicated field %s", ErrInvalidSchema, field.Name)
+		}
+		seen[field.Name] = true
+
+		if field.Type == "" {
+			return fmt.Errorf("%w: field %s has no type", ErrInvalidSchema, field.Name)
+		}
+		imp, err := field.goImport()
+		if err != nil {
+			return err
+		}
+		if imp.Path != "" {
+			e.Imports = append(e.Imports, imp)
+		}
+		structField, err := field.field(e.tags)
+		if err != nil {
+			return err
+		}
+		e.Struct.Fields = append(e.Struct.Fields, structField)
+	}
+	return nil
+}
+
+func (f SchemaField) goType() string {
+	if f.Optional && !strings.HasPrefix(f.Type, "*") && !strings.HasPrefix(f.Type, "[]") && !strings.HasPrefix(f.Type, "map[") {
+		return "*" + f.Type
+	}
+	return f.Type
+}
+
+func (f SchemaField) goImport() (file.Import, error) {
+	if f.Import != "" {
+		return file.Import{Path: f.Import}, nil
+	}
+	qualifier, _, ok := strings.Cut(strings.TrimLeft(f.Type, "*[]"), ".")
+	if !ok {
+		return file.Import{}, nil
+	}
+	if i := strings.LastIndex(qualifier, "]"); i >= 0 {
+		qualifier = qualifier[i+1:]
+	}
+	path, ok := typeImports[qualifier]
+	if !ok {
+		return file.Import{}, fmt.Errorf("%w: field %s uses %s, set its import", ErrInvalidSchema, f.Name, f.Type)
+	}
+	return file.Import{Path: path}, nil
+}
+
+func (f SchemaField) field(tags TagNaming) (file.Field, error) {
+	name := NewEntityName(f.Name, "", "")
+	tag, err := tags.Tag(name, f.Optional, !f.Optional && requirable(f.goType()), f.Tags)
+	if err != nil {
+		return file.Field{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return file.Field{Name: f.Name, Type: f.goType(), Tag: tag.String()}, nil
+}
+
+func (r SchemaRelation) field() (SchemaField, error) {
+	if !isGoName(r.Entity) {
+		return SchemaField{}, fmt.Errorf("%w: relation entity %q must be an exported Go identifier", ErrInvalidSchema, r.Entity)
+	}
+
+	switch r.Kind {
+	case BELONGS_TO_RELATION:
+		name := r.Name
+		if name == "" {
+			name = r.Entity
+		}
+		return SchemaField{Name: name + DEFAULT_ID_FIELD, Type: ID_TYPE}, nil
+	case HAS_ONE_RELATION:
+		name := r.Name
+		if name == "" {
+			name = r.Entity
+		}
+		return SchemaField{Name: name, Type: r.Entity, Optional: true}, nil
+	case HAS_MANY_RELATION:
+		name := r.Name
+		if name == "" {
+			name = NewEntityName(r.Entity, "", "").Plural().PascalCase()
+		}
+		return SchemaField{Name: name, Type: "[]" + r.Entity, Optional: true}, nil
+	}
+	return SchemaField{}, fmt.Errorf("%w: relation kind %q, expected one of %s, %s or %s",
+		ErrInvalidSchema, r.Kind, BELONGS_TO_RELATION, HAS_ONE_RELATION, HAS_MANY_RELATION)
+}
+
+func isGoName(name string) bool {
+	return token.IsIdentifier(name) && token.IsExported(name)
+}
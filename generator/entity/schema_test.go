@@ -0,0 +1,277 @@
+package entity_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestParseEntitySchema(t *testing.T) {
+	t.Run("it should parse yaml schemas with the default id", func(t *testing.T) {
+		src := "name: Order\n" +
+			"fields:\n" +
+			"  - name: Total\n" +
+			"    type: float64\n" +
+			"    tags:\n" +
+			"      db: total\n" +
+			"relations:\n" +
+			"  - entity: Item\n" +
+			"    kind: has_many\n"
+
+		actual, err := entity.ParseEntitySchema([]byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := entity.EntitySchema{
+			Name:      "Order",
+			ID:        entity.SchemaID{Name: "ID"},
+			Fields:    []entity.SchemaField{{Name: "Total", Type: "float64", Tags: map[string]string{"db": "total"}}},
+			Relations: []entity.SchemaRelation{{Entity: "Item", Kind: "has_many"}},
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should parse json schemas", func(t *testing.T) {
+		src := `{"name": "Order", "id": {"name": "OrderID"}, "fields": [{"name": "Note", "type": "string", "optional": true}]}`
+
+		actual, err := entity.ParseEntitySchema([]byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := entity.EntitySchema{
+			Name:   "Order",
+			ID:     entity.SchemaID{Name: "OrderID"},
+			Fields: []entity.SchemaField{{Name: "Note", Type: "string", Optional: true}},
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should reject id types, ids being uuids", func(t *testing.T) {
+		_, err := entity.ParseEntitySchema([]byte("name: Order\nid:\n  type: int64\n"))
+		if !errors.Is(err, entity.ErrInvalidSchema) {
+			utils.Error(t, entity.ErrInvalidSchema, err)
+		}
+	})
+
+	t.Run("it should reject unknown keys", func(t *testing.T) {
+		_, err := entity.ParseEntitySchema([]byte("name: Order\ncolumns: []\n"))
+		if !errors.Is(err, entity.ErrInvalidSchema) {
+			utils.Error(t, entity.ErrInvalidSchema, err)
+		}
+	})
+}
+
+func TestNewEntity(t *testing.T) {
+	schema := entity.EntitySchema{
+		Name: "Order",
+		ID:   entity.SchemaID{Name: "ID"},
+		Fields: []entity.SchemaField{
+			{Name: "Total", Type: "float64", Tags: map[string]string{"db": "total", "bson": "total"}},
+			{Name: "Note", Type: "string", Optional: true},
+			{Name: "PlacedAt", Type: "time.Time"},
+			{Name: "Price", Type: "decimal.Decimal", Import: "github.com/shopspring/decimal", Tags: map[string]string{"json": "price,string"}},
+		},
+		Relations: []entity.SchemaRelation{
+			{Entity: "Customer", Kind: "belongs_to"},
+			{Name: "Shipping", Entity: "Address", Kind: "has_one"},
+			{Entity: "Item", Kind: "has_many"},
+		},
+	}
+
+	t.Run("it should return the struct file", func(t *testing.T) {
+		e, err := entity.NewEntity(schema, "src/structs", "github.com/eduardoths/microservice")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := "src/structs/order.go"; want != e.FilePath() {
+			utils.Error(t, want, e.FilePath())
+		}
+		actual := e.File().String()
+		want := "package structs\n\n" +
+			"import (\n" +
+			"\t\"github.com/google/uuid\"\n" +
+			"\t\"github.com/shopspring/decimal\"\n" +
+			"\t\"time\"\n" +
+			")\n\n" +
+			"type Order struct {\n" +
+			"\tID uuid.UUID `json:\"id\"`\n" +
+			"\tTotal float64 `json:\"total\" bson:\"total\" db:\"total\"`\n" +
+			"\tNote *string `json:\"note,omitempty\"`\n" +
+			"\tPlacedAt time.Time `json:\"placed_at\"`\n" +
+			"\tPrice decimal.Decimal `json:\"price,string\"`\n" +
+			"\tCustomerID uuid.UUID `json:\"customer_id\"`\n" +
+			"\tShipping *Address `json:\"shipping,omitempty\"`\n" +
+			"\tItems []Item `json:\"items,omitempty\"`\n" +
+			"}\n"
+		if want != actual {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should populate the tags named after the strategy of their key", func(t *testing.T) {
+		e, err := entity.NewEntityWithTags(schema, "src/structs", "github.com/eduardoths/microservice", entity.TagNaming{
+			"json":     entity.NAMING_CAMEL,
+			"db":       entity.NAMING_SNAKE,
+			"yaml":     entity.NAMING_KEBAB,
+			"validate": entity.NAMING_SNAKE,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tags := make([]string, 0)
+		for _, field := range e.Struct.Fields[:5] {
+			tags = append(tags, field.Tag)
+		}
+		want := []string{
+			"`json:\"id\" db:\"id\" yaml:\"id\"`",
+			"`json:\"total\" bson:\"total\" db:\"total\" yaml:\"total\"`",
+			"`json:\"note,omitempty\" db:\"note\" yaml:\"note,omitempty\"`",
+			"`json:\"placedAt\" db:\"placed_at\" yaml:\"placed-at\"`",
+			"`json:\"price,string\" db:\"price\" yaml:\"price\"`",
+		}
+		if strings.Join(want, "\n") != strings.Join(tags, "\n") {
+			utils.Error(t, strings.Join(want, "\n"), strings.Join(tags, "\n"))
+		}
+	})
+
+	t.Run("it should only require the string, slice, map and pointer fields", func(t *testing.T) {
+		e, err := entity.NewEntityWithTags(entity.EntitySchema{
+			Name: "Order",
+			ID:   entity.SchemaID{Name: "ID"},
+			Fields: []entity.SchemaField{
+				{Name: "Code", Type: "string"},
+				{Name: "Lines", Type: "[]string"},
+				{Name: "Labels", Type: "map[string]string"},
+				{Name: "Parent", Type: "*Order"},
+				{Name: "Note", Type: "string", Optional: true},
+				{Name: "Count", Type: "int"},
+				{Name: "Paid", Type: "bool"},
+			},
+		}, "src/structs", "github.com/eduardoths/microservice", entity.TagNaming{"validate": entity.NAMING_SNAKE})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		required := make([]string, 0)
+		for _, field := range e.Struct.Fields {
+			if strings.Contains(field.Tag, "required") {
+				required = append(required, field.Name)
+			}
+		}
+		if want := "Code Lines Labels Parent"; want != strings.Join(required, " ") {
+			utils.Error(t, want, strings.Join(required, " "))
+		}
+	})
+
+	t.Run("it should reject tags holding backticks", func(t *testing.T) {
+		_, err := entity.NewEntity(entity.EntitySchema{
+			Name: "Order",
+			ID:   entity.SchemaID{Name: "ID"},
+			Fields: []entity.SchemaField{
+				{Name: "Code", Type: "string", Tags: map[string]string{"db": "co`de"}},
+			},
+		}, "src/structs", "github.com/eduardoths/microservice")
+		if !errors.Is(err, entity.ErrInvalidSchema) {
+			utils.Error(t, entity.ErrInvalidSchema, err)
+		}
+	})
+
+	t.Run("it should be usable by the other generators", func(t *testing.T) {
+		e, err := entity.NewEntity(schema, "src/structs", "github.com/eduardoths/microservice")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repo := entity.NewRepository(e.Name(), "github.com/eduardoths/microservice")
+		want := "src/repositories/order/order_repository.go"
+		if want != repo.FilePath() {
+			utils.Error(t, want, repo.FilePath())
+		}
+	})
+
+	t.Run("it should fail for invalid schemas", func(t *testing.T) {
+		type testCase struct {
+			it     string
+			schema entity.EntitySchema
+		}
+
+		id := entity.SchemaID{Name: "ID"}
+		tc := []testCase{
+			{it: "should fail without a name", schema: entity.EntitySchema{ID: id}},
+			{it: "should fail with unexported names", schema: entity.EntitySchema{Name: "order", ID: id}},
+			{
+				it: "should fail with duplicated fields",
+				schema: entity.EntitySchema{Name: "Order", ID: id, Fields: []entity.SchemaField{
+					{Name: "ID", Type: "string"},
+				}},
+			},
+			{
+				it: "should fail with fields without type",
+				schema: entity.EntitySchema{Name: "Order", ID: id, Fields: []entity.SchemaField{
+					{Name: "Total"},
+				}},
+			},
+			{
+				it: "should fail with unknown qualified types without import",
+				schema: entity.EntitySchema{Name: "Order", ID: id, Fields: []entity.SchemaField{
+					{Name: "Price", Type: "decimal.Decimal"},
+				}},
+			},
+			{
+				it: "should fail with unknown relation kinds",
+				schema: entity.EntitySchema{Name: "Order", ID: id, Relations: []entity.SchemaRelation{
+					{Entity: "Item", Kind: "many_to_many"},
+				}},
+			},
+		}
+
+		for _, c := range tc {
+			t.Run(c.it, func(t *testing.T) {
+				_, err := entity.NewEntity(c.schema, "src/structs", "github.com/eduardoths/microservice")
+				if !errors.Is(err, entity.ErrInvalidSchema) {
+					utils.Error(t, entity.ErrInvalidSchema, err)
+				}
+			})
+		}
+	})
+}
+
+func TestParseTagNaming(t *testing.T) {
+	type testCase struct {
+		it           string
+		raw          string
+		wantKey      string
+		wantStrategy string
+		wantErr      error
+	}
+
+	tc := []testCase{
+		{it: "it should default to snake case", raw: "db", wantKey: "db", wantStrategy: entity.NAMING_SNAKE},
+		{it: "it should read the strategy", raw: "json=camel", wantKey: "json", wantStrategy: entity.NAMING_CAMEL},
+		{it: "it should fail on unknown strategies", raw: "json=pascal", wantErr: entity.ErrInvalidTagNaming},
+		{it: "it should fail on invalid keys", raw: "js on=snake", wantErr: entity.ErrInvalidTagNaming},
+		{it: "it should fail without key", raw: "=snake", wantErr: entity.ErrInvalidTagNaming},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			key, strategy, err := entity.ParseTagNaming(c.raw)
+			if !errors.Is(err, c.wantErr) {
+				utils.Error(t, c.wantErr, err)
+			}
+			if key != c.wantKey || strategy != c.wantStrategy {
+				utils.Error(t, c.wantKey+"="+c.wantStrategy, key+"="+strategy)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/utils"
+)
+
+type Service struct {
+	serviceName EntityName
+	repository  Repository
+	config      ImplementationConfig
+	implStruct  file.Struct
+
+	Interface file.Interface
+	Imports   file.Imports
+	Vars      []file.Var
+	Types     []file.Type
+}
+
+func NewService(structName EntityName, basePkg string) Service {
+	return NewServiceWithConfig(structName, basePkg, DefaultImplementationConfig())
+}
+
+func NewServiceWithConfig(structName EntityName, basePkg string, config ImplementationConfig) Service {
+	service := Service{
+		serviceName: serviceName(structName, basePkg),
+		repository:  NewRepository(structName, basePkg),
+		config:      config,
+	}
+	service.build()
+
+	return service
+}
+
+// serviceName returns the name of the service interface of the entity, in
+// its package.
+func serviceName(structName EntityName, basePkg string) EntityName {
+	return NewEntityName(
+		structName.PascalCase()+"Service",
+		utils.MergePaths(SERVICES_PATH, structName.SnakeCase()),
+		basePkg,
+	)
+}
+
+func (s *Service) build() {
+	s.buildInterface()
+	s.buildImports()
+	s.buildImplementation()
+}
+
+func (s *Service) File() file.File {
+	return file.File{
+		Package:    s.serviceName.ImportName(),
+		Imports:    s.Imports,
+		Vars:       s.Vars,
+		Types:      s.Types,
+		Interfaces: []file.Interface{s.Interface},
+		Structs:    []file.Struct{s.implStruct},
+	}
+}
+
+func (s *Service) buildInterface() {
+	s.Interface = file.Interface{
+		Name:    s.serviceName.PascalCase(),
+		Methods: []file.Method{},
+	}
+
+	for _, imethod := range s.internalMethods() {
+		s.Interface.Methods = append(s.Interface.Methods, imethod.method)
+	}
+}
+
+func (s *Service) buildImports() {
+	s.Imports = make(file.Imports, 0)
+	for _, imethod := range s.internalMethods() {
+		s.Imports = append(s.Imports, imethod.imports...)
+	}
+	s.Imports = append(s.Imports, s.implementation().imports()...)
+}
+
+func (s *Service) buildImplementation() {
+	impl := s.implementation()
+	s.implStruct = impl.build()
+	s.Types = impl.types()
+	s.Vars = append(impl.vars(), notFoundVar(s.repository.structName))
+}
+
+func (s Service) implementation() implementation {
+	config := s.config
+	config.Dependencies = append([]Dependency{s.repositoryDependency()}, s.config.Dependencies...)
+	return implementation{
+		iface:   s.serviceName,
+		config:  config,
+		methods: s.internalMethods(),
+	}
+}
+
+// alias returns the alias of the receivers of the implementation, computed
+// from the repository methods it implements since its own are built with it.
+func (s Service) alias() string {
+	repoMethods := s.repository.internalMethods()
+	methods := make([]file.Method, 0, len(repoMethods))
+	for _, repoMethod := range repoMethods {
+		methods = append(methods, repoMethod.method)
+	}
+	return receiverAlias(s.serviceName, methods, append([]Dependency{s.repositoryDependency()}, s.config.Dependencies...))
+}
+
+func (s Service) repositoryDependency() Dependency {
+	return Dependency{
+		Name:     "repository",
+		Type:     s.repositoryPkg() + "." + s.repository.repoName.PascalCase(),
+		Import:   s.repositoryImport(),
+		Required: true,
+	}
+}
+
+// repositoryImport aliases the repository package when its name is taken by
+// the entity parameters, which happens for single word entities.
+func (s Service) repositoryImport() file.Import {
+	imp := s.repository.repoName.FileImport()
+	if s.repository.repoName.ImportName() == s.repository.structName.CamelCase() {
+		imp.Name = s.repository.repoName.ImportName() + "repository"
+	}
+	return imp
+}
+
+func (s Service) repositoryPkg() string {
+	if imp := s.repositoryImport(); imp.Name != "" {
+		return imp.Name
+	}
+	return s.repository.repoName.ImportName()
+}
+
+func (s Service) internalMethods() []imethod {
+	repoMethods := s.repository.internalMethods()
+	methods := make([]imethod, 0, len(repoMethods))
+	for _, repoMethod := range repoMethods {
+		methods = append(methods, s.useCaseMethod(repoMethod))
+	}
+	return methods
+}
+
+func (s Service) useCaseMethod(repoMethod imethod) imethod {
+	imports := append(file.Imports{
+		{Path: ERRORS_PKG},
+		s.repositoryImport(),
+	}, repoMethod.imports...)
+
+	return imethod{
+		method:         repoMethod.method,
+		imports:        imports,
+		implementation: s.delegateTo(repoMethod.method),
+	}
+}
+
+func (s Service) delegateTo(method file.Method) []string {
+	params := make([]string, 0, len(method.Params))
+	for _, param := range method.Params {
+		if param.Variadic {
+			params = append(params, param.Name+"...")
+			continue
+		}
+		params = append(params, param.Name)
+	}
+	results := make([]string, 0, len(method.Results))
+	notFoundResults := make([]string, 0, len(method.Results))
+	for _, result := range method.Results {
+		results = append(results, result.Name)
+		if result.Type == "error" {
+			notFoundResults = append(notFoundResults, "ErrNotFound")
+		} else {
+			notFoundResults = append(notFoundResults, result.Name)
+		}
+	}
+
+	call := s.alias() + ".repository." + method.Name + "(" + strings.Join(params, ", ") + ")"
+	return []string{
+		strings.Join(results, ", ") + " = " + call,
+		"if errors.Is(err, " + s.repositoryPkg() + ".ErrNotFound) {",
+		"\treturn " + strings.Join(notFoundResults, ", "),
+		"}",
+		"return " + strings.Join(results, ", "),
+	}
+}
+
+func (s Service) FilePath() string {
+	return s.serviceName.FilePath()
+}
+
+func (s Service) TestFilePath() string {
+	return testFilePath(s.FilePath())
+}
+
+// TestFile returns the table-driven tests of the service methods, run
+// against an in-memory fake of the repository.
+func (s Service) TestFile() file.File {
+	imports := file.Imports{
+		{Path: CONTEXT_PKG},
+		{Path: ERRORS_PKG},
+		{Path: "testing"},
+		{Path: ID_PKG},
+		s.repositoryImport(),
+	}
+	cases := make([]methodTestCase, 0)
+	for _, imethod := range s.repository.internalMethods() {
+		imports = append(imports, imethod.imports...)
+		words, subject := methodTestWords(s.repository.structName, imethod.method)
+		cases = append(cases,
+			methodTestCase{it: "should " + words + " " + subject, method: imethod.method},
+			methodTestCase{
+				it:      "should return the repository errors when " + words + " fails",
+				method:  imethod.method,
+				err:     "unexpected",
+				wantErr: "unexpected",
+			},
+		)
+		if hasParam(imethod.method, ID_TYPE) {
+			cases = append(cases, methodTestCase{
+				it:      "should return ErrNotFound when " + words + " does not find the " + subject,
+				method:  imethod.method,
+				err:     s.repositoryPkg() + ".ErrNotFound",
+				wantErr: "ErrNotFound",
+			})
+		}
+	}
+
+	repository := fake(NewEntityName("fake"+s.repository.repoName.PascalCase(), "", ""), s.repository.Interface.MethodSet())
+	return file.File{
+		Package: s.serviceName.ImportName(),
+		Imports: imports,
+		Funcs: []file.Implementation{
+			{
+				Func: file.Method{
+					Name:   "Test" + s.serviceName.PascalCase(),
+					Params: file.Args{{Name: "t", Type: "*testing.T"}},
+				},
+				CodeLines: methodTestBody(s.serviceName.PascalCase(), "service", cases,
+					[]string{`unexpected := errors.New("unexpected")`},
+					[]string{"err := c.call(" + s.implementation().literalStart() + "repository: " + repository.Name + "{err: c.err}})"},
+				),
+			},
+		},
+		Structs: []file.Struct{repository},
+	}
+}
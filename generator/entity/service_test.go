@@ -0,0 +1,298 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestNewService(t *testing.T) {
+	t.Run("it should return valid interfaces", func(t *testing.T) {
+		service := entity.NewService(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		actual := service.Interface
+		want := "\ntype XptoStructNameService interface {\n" +
+			"\tGetAll(ctx context.Context) (xptoStructName []structs.XptoStructName, err error)\n" +
+			"\tGet(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error)\n" +
+			"\tCreate(ctx context.Context, xptoStructName structs.XptoStructName) (created structs.XptoStructName, err error)\n" +
+			"\tUpdate(ctx context.Context, id uuid.UUID, xptoStructName structs.XptoStructName) (updated structs.XptoStructName, err error)\n" +
+			"\tDelete(ctx context.Context, id uuid.UUID) (err error)\n" +
+			"}\n"
+		if want != actual.String() {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should return the service file path", func(t *testing.T) {
+		service := entity.NewService(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		actual := service.FilePath()
+		want := "src/services/xpto_struct_name/xpto_struct_name_service.go"
+		if want != actual {
+			utils.Error(t, want, actual)
+		}
+		if want := "src/services/xpto_struct_name/xpto_struct_name_service_test.go"; want != service.TestFilePath() {
+			utils.Error(t, want, service.TestFilePath())
+		}
+	})
+
+	t.Run("it should return valid file", func(t *testing.T) {
+		service := entity.NewService(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		actual := service.File()
+		want := "package xptostructname\n\n" +
+			"import (\n" +
+			"\t\"context\"\n" +
+			"\t\"errors\"\n" +
+			"\txptostructname \"github.com/eduardoths/microservice/src/repositories/xpto_struct_name\"\n" +
+			"\t\"github.com/eduardoths/microservice/src/structs\"\n" +
+			"\t\"github.com/google/uuid\"\n" +
+			")\n\n" +
+			"var _ XptoStructNameService = (*xptoStructNameService)(nil)\n\n" +
+			"var ErrNotFound = errors.New(\"xpto struct name not found\")\n\n" +
+			"type XptoStructNameService interface {\n" +
+			"\tGetAll(ctx context.Context) (xptoStructName []structs.XptoStructName, err error)\n" +
+			"\tGet(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error)\n" +
+			"\tCreate(ctx context.Context, xptoStructName structs.XptoStructName) (created structs.XptoStructName, err error)\n" +
+			"\tUpdate(ctx context.Context, id uuid.UUID, xptoStructName structs.XptoStructName) (updated structs.XptoStructName, err error)\n" +
+			"\tDelete(ctx context.Context, id uuid.UUID) (err error)\n" +
+			"}\n\n" +
+			"type xptoStructNameService struct {\n" +
+			"\trepository xptostructname.XptoStructNameRepository\n" +
+			"}\n\n" +
+			"func NewXptoStructNameService(repository xptostructname.XptoStructNameRepository) XptoStructNameService {\n" +
+			"\treturn &xptoStructNameService{\n" +
+			"\t\trepository: repository,\n" +
+			"\t}\n" +
+			"}\n\n" +
+			"func (xsns *xptoStructNameService) GetAll(ctx context.Context) (xptoStructName []structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameService.GetAll\n" +
+			"\txptoStructName, err = xsns.repository.GetAll(ctx)\n" +
+			"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+			"\t\treturn xptoStructName, ErrNotFound\n" +
+			"\t}\n" +
+			"\treturn xptoStructName, err\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsns *xptoStructNameService) Get(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameService.Get\n" +
+			"\txptoStructName, err = xsns.repository.Get(ctx, id)\n" +
+			"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+			"\t\treturn xptoStructName, ErrNotFound\n" +
+			"\t}\n" +
+			"\treturn xptoStructName, err\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsns *xptoStructNameService) Create(ctx context.Context, xptoStructName structs.XptoStructName) (created structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameService.Create\n" +
+			"\tcreated, err = xsns.repository.Create(ctx, xptoStructName)\n" +
+			"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+			"\t\treturn created, ErrNotFound\n" +
+			"\t}\n" +
+			"\treturn created, err\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsns *xptoStructNameService) Update(ctx context.Context, id uuid.UUID, xptoStructName structs.XptoStructName) (updated structs.XptoStructName, err error) {\n" +
+			"\t// microcli:begin xptoStructNameService.Update\n" +
+			"\tupdated, err = xsns.repository.Update(ctx, id, xptoStructName)\n" +
+			"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+			"\t\treturn updated, ErrNotFound\n" +
+			"\t}\n" +
+			"\treturn updated, err\n" +
+			"\t// microcli:end\n" +
+			"}\n\n" +
+			"func (xsns *xptoStructNameService) Delete(ctx context.Context, id uuid.UUID) (err error) {\n" +
+			"\t// microcli:begin xptoStructNameService.Delete\n" +
+			"\terr = xsns.repository.Delete(ctx, id)\n" +
+			"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+			"\t\treturn ErrNotFound\n" +
+			"\t}\n" +
+			"\treturn err\n" +
+			"\t// microcli:end\n" +
+			"}\n"
+		if want != actual.String() {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should return valid file", func(t *testing.T) {
+		service := entity.NewService(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		actual := service.File().String()
+		wantParts := []string{
+			"package xptostructname\n\n" +
+				"import (\n" +
+				"\t\"context\"\n" +
+				"\t\"errors\"\n" +
+				"\txptostructname \"github.com/eduardoths/microservice/src/repositories/xpto_struct_name\"\n" +
+				"\t\"github.com/eduardoths/microservice/src/structs\"\n" +
+				"\t\"github.com/google/uuid\"\n" +
+				")\n\n" +
+				"var _ XptoStructNameService = (*xptoStructNameService)(nil)\n\n" +
+				"var ErrNotFound = errors.New(\"xpto struct name not found\")\n\n",
+			"type xptoStructNameService struct {\n" +
+				"\trepository xptostructname.XptoStructNameRepository\n" +
+				"}\n\n" +
+				"func NewXptoStructNameService(repository xptostructname.XptoStructNameRepository) XptoStructNameService {\n" +
+				"\treturn &xptoStructNameService{\n" +
+				"\t\trepository: repository,\n" +
+				"\t}\n" +
+				"}\n\n",
+			"func (xsns *xptoStructNameService) Get(ctx context.Context, id uuid.UUID) (xptoStructName structs.XptoStructName, err error) {\n" +
+				"\t// microcli:begin xptoStructNameService.Get\n" +
+				"\txptoStructName, err = xsns.repository.Get(ctx, id)\n" +
+				"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+				"\t\treturn xptoStructName, ErrNotFound\n" +
+				"\t}\n" +
+				"\treturn xptoStructName, err\n" +
+				"\t// microcli:end\n" +
+				"}\n",
+			"func (xsns *xptoStructNameService) Delete(ctx context.Context, id uuid.UUID) (err error) {\n" +
+				"\t// microcli:begin xptoStructNameService.Delete\n" +
+				"\terr = xsns.repository.Delete(ctx, id)\n" +
+				"\tif errors.Is(err, xptostructname.ErrNotFound) {\n" +
+				"\t\treturn ErrNotFound\n" +
+				"\t}\n" +
+				"\treturn err\n" +
+				"\t// microcli:end\n" +
+				"}\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+	})
+
+	t.Run("it should append extra dependencies after the repository", func(t *testing.T) {
+		config := entity.DefaultImplementationConfig()
+		config.Dependencies = []entity.Dependency{{Name: "clock", Type: "func() time.Time"}}
+		service := entity.NewServiceWithConfig(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := service.File().String()
+		want := "func NewXptoStructNameService(repository xptostructname.XptoStructNameRepository, clock func() time.Time) XptoStructNameService {\n"
+		if !strings.Contains(actual, want) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should take the optional dependencies as functional options", func(t *testing.T) {
+		config := entity.DefaultImplementationConfig()
+		config.FunctionalOptions = true
+		config.Dependencies = []entity.Dependency{
+			{Name: "timeout", Type: "time.Duration", Default: "5 * time.Second", Import: file.Import{Path: "time"}},
+			{Name: "addr", Type: "string"},
+		}
+		service := entity.NewServiceWithConfig(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := service.File().String()
+		wantParts := []string{
+			"type XptoStructNameServiceOption func(*xptoStructNameService)\n",
+			"func NewXptoStructNameService(repository xptostructname.XptoStructNameRepository, opts ...XptoStructNameServiceOption) XptoStructNameService {\n" +
+				"\txsns := &xptoStructNameService{\n" +
+				"\t\trepository: repository,\n" +
+				"\t\ttimeout: 5 * time.Second,\n" +
+				"\t}\n" +
+				"\tfor _, opt := range opts {\n" +
+				"\t\topt(xsns)\n" +
+				"\t}\n" +
+				"\treturn xsns\n" +
+				"}\n",
+			"func WithTimeout(timeout time.Duration) XptoStructNameServiceOption {\n" +
+				"\treturn func(xsns *xptoStructNameService) {\n" +
+				"\t\txsns.timeout = timeout\n" +
+				"\t}\n" +
+				"}\n",
+			"func WithAddr(addr string) XptoStructNameServiceOption {\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+	})
+
+	t.Run("it should rename the locals of the options named like a dependency", func(t *testing.T) {
+		config := entity.DefaultImplementationConfig()
+		config.FunctionalOptions = true
+		config.Dependencies = []entity.Dependency{
+			{Name: "xsns", Type: "string"},
+			{Name: "opts", Type: "string", Required: true},
+		}
+		service := entity.NewServiceWithConfig(
+			entity.NewEntityName("XptoStructName", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+			config,
+		)
+
+		actual := service.File().String()
+		wantParts := []string{
+			"func NewXptoStructNameService(repository xptostructname.XptoStructNameRepository, opts string, opts2 ...XptoStructNameServiceOption) XptoStructNameService {\n" +
+				"\txsns2 := &xptoStructNameService{\n" +
+				"\t\trepository: repository,\n" +
+				"\t\topts: opts,\n" +
+				"\t}\n" +
+				"\tfor _, opt := range opts2 {\n" +
+				"\t\topt(xsns2)\n" +
+				"\t}\n" +
+				"\treturn xsns2\n" +
+				"}\n",
+			"func WithXsns(xsns string) XptoStructNameServiceOption {\n" +
+				"\treturn func(xsns2 *xptoStructNameService) {\n" +
+				"\t\txsns2.xsns = xsns\n" +
+				"\t}\n" +
+				"}\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+	})
+
+	t.Run("it should alias the repository package of single word entities", func(t *testing.T) {
+		service := entity.NewService(
+			entity.NewEntityName("Order", "src/structs", "github.com/eduardoths/microservice"),
+			"github.com/eduardoths/microservice",
+		)
+
+		actual := service.File().String()
+		wantParts := []string{
+			"	orderrepository \"github.com/eduardoths/microservice/src/repositories/order\"\n",
+			"\trepository orderrepository.OrderRepository\n",
+			"\tif errors.Is(err, orderrepository.ErrNotFound) {\n",
+		}
+		for _, want := range wantParts {
+			if !strings.Contains(actual, want) {
+				utils.Error(t, want, actual)
+			}
+		}
+		actualTest := service.TestFile().String()
+		if want := "\t\t\terr: orderrepository.ErrNotFound,\n"; !strings.Contains(actualTest, want) {
+			utils.Error(t, want, actualTest)
+		}
+	})
+}
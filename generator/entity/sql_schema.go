@@ -0,0 +1,378 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/eduardoths/micro-cli/utils"
+)
+
+var (
+	ErrInvalidSQL         = errors.New("invalid SQL schema")
+	ErrUnsupportedSQLType = errors.New("unsupported SQL type")
+
+	// errSkippedTable marks the tables left out of an import.
+	errSkippedTable = errors.New("skipped table")
+)
+
+const (
+	NULLABLE_POINTER = "pointer"
+	NULLABLE_SQL     = "sql"
+
+	DIALECT_POSTGRES = "postgres"
+	DIALECT_MYSQL    = "mysql"
+)
+
+type sqlType struct {
+	pattern *regexp.Regexp
+	goType  string
+}
+
+// sqlTypes maps PostgreSQL and MySQL column types to Go types, the first
+// matching pattern wins.
+var sqlTypes = []sqlType{
+	{regexp.MustCompile(`^uuid\b`), ID_TYPE},
+	{regexp.MustCompile(`^(tinyint\s*\(\s*1\s*\)|(bool|boolean)\b)`), "bool"},
+	{regexp.MustCompile(`^tinyint\b.*\bunsigned\b`), "uint8"},
+	{regexp.MustCompile(`^tinyint\b`), "int8"},
+	{regexp.MustCompile(`^(smallint|int2|smallserial|serial2)\b.*\bunsigned\b`), "uint16"},
+	{regexp.MustCompile(`^(smallint|int2|smallserial|serial2)\b`), "int16"},
+	{regexp.MustCompile(`^(bigint|int8|bigserial|serial8)\b.*\bunsigned\b`), "uint64"},
+	{regexp.MustCompile(`^(bigint|int8|bigserial|serial8)\b`), "int64"},
+	{regexp.MustCompile(`^(integer|int|int4|mediumint|serial|serial4)\b.*\bunsigned\b`), "uint32"},
+	{regexp.MustCompile(`^(integer|int|int4|mediumint|serial|serial4)\b`), "int32"},
+	{regexp.MustCompile(`^(real|float4)\b`), "float32"},
+	{regexp.MustCompile(`^(double precision|double|float8|float)\b`), "float64"},
+	{regexp.MustCompile(`^(decimal|numeric|money)\b`), "string"},
+	{regexp.MustCompile(`^(character varying|varchar|character|char|nchar|nvarchar|text|tinytext|mediumtext|longtext|citext|enum|set|inet|cidr|macaddr|xml)\b`), "string"},
+	{regexp.MustCompile(`^(bytea|blob|tinyblob|mediumblob|longblob|binary|varbinary|bit)\b`), "[]byte"},
+	{regexp.MustCompile(`^(timestamp|timestamptz|datetime|date|time|timetz|year)\b`), "time.Time"},
+	{regexp.MustCompile(`^(json|jsonb)\b`), "json.RawMessage"},
+}
+
+// uuidKeyTypes are the column types that hold UUIDs when used as primary keys.
+var uuidKeyTypes = regexp.MustCompile(`^(uuid|char\s*\(\s*36\s*\)|binary\s*\(\s*16\s*\)|varchar\s*\(\s*36\s*\))`)
+
+// sqlNullTypes are the database/sql Null types of nullable columns, widened
+// to the smallest type holding every value of the column when database/sql
+// has none of its size. uint64 columns, which no Null type holds, stay
+// pointers, and byte slices stay nil when null, as database/sql scans them.
+var sqlNullTypes = map[string]string{
+	"string":    "sql.NullString",
+	"bool":      "sql.NullBool",
+	"int8":      "sql.NullInt16",
+	"int16":     "sql.NullInt16",
+	"int32":     "sql.NullInt32",
+	"int64":     "sql.NullInt64",
+	"uint8":     "sql.NullByte",
+	"uint16":    "sql.NullInt32",
+	"uint32":    "sql.NullInt64",
+	"float32":   "sql.NullFloat64",
+	"float64":   "sql.NullFloat64",
+	"time.Time": "sql.NullTime",
+	ID_TYPE:     "uuid.NullUUID",
+}
+
+var (
+	dollarQuote     = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+	createTable     = regexp.MustCompile(`(?is)^create\s+(?:(?:global\s+|local\s+)?(?:temporary|temp|unlogged)\s+)?table\s+(?:if\s+not\s+exists\s+)?([^\s(]+)\s*\(`)
+	tableConstraint = regexp.MustCompile(`(?i)^(constraint|primary\s+key|unique|key|index|fulltext|spatial|foreign\s+key|check|exclude)\b`)
+	primaryKeyList  = regexp.MustCompile(`(?i)primary\s+key\s*\(([^)]*)\)`)
+	primaryKey      = regexp.MustCompile(`(?i)\bprimary\s+key\b`)
+	notNull         = regexp.MustCompile(`(?i)\bnot\s+null\b`)
+)
+
+type sqlColumn struct {
+	name       string
+	definition string
+	primaryKey bool
+	notNull    bool
+}
+
+// ParseSQLSchemas reads the CREATE TABLE statements of a DDL script of the
+// dialect, DIALECT_POSTGRES or DIALECT_MYSQL, into entity schemas. Nullable
+// columns become pointers, or database/sql Null types when nullable is
+// NULLABLE_SQL. The tables without a single UUID primary key, which the
+// generated layers identify entities by, are skipped: the reasons are
+// returned along with the schemas, and the script is only rejected when no
+// table is left.
+func ParseSQLSchemas(ddl string, dialect string, nullable string) ([]EntitySchema, []error, error) {
+	if dialect != DIALECT_POSTGRES && dialect != DIALECT_MYSQL {
+		return nil, nil, fmt.Errorf("%w: dialect %q, expected %s or %s", ErrInvalidSQL, dialect, DIALECT_POSTGRES, DIALECT_MYSQL)
+	}
+	if nullable != NULLABLE_POINTER && nullable != NULLABLE_SQL {
+		return nil, nil, fmt.Errorf("%w: nullable style %q, expected %s or %s", ErrInvalidSQL, nullable, NULLABLE_POINTER, NULLABLE_SQL)
+	}
+
+	schemas := make([]EntitySchema, 0)
+	skipped := make([]error, 0)
+	tables := 0
+	for _, statement := range sqlStatements(ddl, dialect) {
+		statement = strings.TrimSpace(statement)
+		match := createTable.FindStringSubmatchIndex(statement)
+		if match == nil {
+			continue
+		}
+		tables++
+		table := unquoteSQLName(statement[match[2]:match[3]])
+		body, err := parenthesized(statement[match[1]-1:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: table %s: %v", ErrInvalidSQL, table, err)
+		}
+		schema, err := tableSchema(table, splitSQL(body, ','), nullable)
+		if errors.Is(err, errSkippedTable) {
+			skipped = append(skipped, err)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	if tables == 0 {
+		return nil, nil, fmt.Errorf("%w: no CREATE TABLE statement found", ErrInvalidSQL)
+	}
+	if len(schemas) == 0 {
+		reasons := make([]string, 0, len(skipped))
+		for _, err := range skipped {
+			reasons = append(reasons, err.Error())
+		}
+		return nil, nil, fmt.Errorf("%w: no table has a single UUID primary key: %s", ErrInvalidSQL, strings.Join(reasons, "; "))
+	}
+	return schemas, skipped, nil
+}
+
+func tableSchema(table string, definitions []string, nullable string) (EntitySchema, error) {
+	columns := make([]sqlColumn, 0, len(definitions))
+	keys := make(map[string]bool)
+	for _, definition := range definitions {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+		if tableConstraint.MatchString(definition) {
+			if match := primaryKeyList.FindStringSubmatch(definition); match != nil {
+				for _, key := range strings.Split(match[1], ",") {
+					keys[unquoteSQLName(strings.TrimSpace(key))] = true
+				}
+			}
+			continue
+		}
+
+		name, rest := splitSQLName(definition)
+		column := sqlColumn{
+			name:       name,
+			definition: strings.ToLower(strings.TrimSpace(rest)),
+			primaryKey: primaryKey.MatchString(rest),
+			notNull:    notNull.MatchString(rest),
+		}
+		if column.primaryKey {
+			keys[name] = true
+		}
+		columns = append(columns, column)
+	}
+	switch {
+	case len(keys) == 0:
+		return EntitySchema{}, fmt.Errorf("%w %s: no primary key", errSkippedTable, table)
+	case len(keys) > 1:
+		return EntitySchema{}, fmt.Errorf("%w %s: composite primary key", errSkippedTable, table)
+	}
+
+	schema := EntitySchema{Name: NewEntityName(utils.Singularize(table), "", "").PascalCase()}
+	for _, column := range columns {
+		tags := map[string]string{"db": column.name, "json": column.name}
+		if keys[column.name] {
+			if !uuidKeyTypes.MatchString(column.definition) {
+				return EntitySchema{}, fmt.Errorf("%w %s: primary key %s is %s, only uuid, char(36), "+
+					"varchar(36) and binary(16) keys are supported as generated layers identify entities by %s",
+					errSkippedTable, table, column.name, column.typeName(), ID_TYPE)
+			}
+			schema.ID = SchemaID{Name: NewEntityName(column.name, "", "").FieldName(), Tags: tags}
+			continue
+		}
+
+		field, err := column.field(nullable)
+		if err != nil {
+			return EntitySchema{}, fmt.Errorf("table %s: %w", table, err)
+		}
+		field.Tags = tags
+		if field.Optional {
+			field.Tags["json"] += ",omitempty"
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+	return schema, nil
+}
+
+func (c sqlColumn) field(nullable string) (SchemaField, error) {
+	goType := ""
+	for _, t := range sqlTypes {
+		if t.pattern.MatchString(c.definition) {
+			goType = t.goType
+			break
+		}
+	}
+	if goType == "" {
+		return SchemaField{}, fmt.Errorf("%w: column %s is %s", ErrUnsupportedSQLType, c.name, c.typeName())
+	}
+	if strings.Contains(c.definition, "[]") {
+		goType = "[]" + goType
+	}
+
+	field := SchemaField{Name: NewEntityName(c.name, "", "").FieldName(), Type: goType}
+	if c.notNull || strings.HasPrefix(goType, "[]") || goType == "json.RawMessage" {
+		return field, nil
+	}
+	if nullType, ok := sqlNullTypes[goType]; ok && nullable == NULLABLE_SQL {
+		field.Type = nullType
+		return field, nil
+	}
+	field.Optional = true
+	return field, nil
+}
+
+func (c sqlColumn) typeName() string {
+	if fields := strings.Fields(c.definition); len(fields) > 0 {
+		return fields[0]
+	}
+	return "untyped"
+}
+
+// sqlStatements splits the script into its statements, dropping the
+// comments out of quotes: the -- and /* */ ones, and the # ones of MySQL,
+// where backslashes escape quotes too. The dollar-quoted bodies of PostgreSQL
+// are kept whole.
+func sqlStatements(ddl string, dialect string) []string {
+	statements := make([]string, 0)
+	var sb strings.Builder
+	// quote closes the quoted text being read, if any
+	quote := ""
+	for i := 0; i < len(ddl); i++ {
+		c, rest := ddl[i], ddl[i:]
+		if quote != "" {
+			switch {
+			case dialect == DIALECT_MYSQL && c == '\\' && quote != "`" && i+1 < len(ddl):
+				// escaped quotes are doubled as in standard SQL, which the
+				// other scans of the statement read
+				if escaped := ddl[i+1 : i+2]; escaped == quote {
+					sb.WriteString(quote + quote)
+				} else {
+					sb.WriteString(ddl[i : i+2])
+				}
+				i++
+			case strings.HasPrefix(rest, quote):
+				sb.WriteString(quote)
+				i += len(quote) - 1
+				quote = ""
+			default:
+				sb.WriteByte(c)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(rest, "--") || (c == '#' && dialect == DIALECT_MYSQL):
+			// the newline ending the comment is kept
+			if end := strings.IndexByte(rest, '\n'); end >= 0 {
+				i += end - 1
+			} else {
+				i = len(ddl)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			sb.WriteByte(' ')
+			if end := strings.Index(rest[2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(ddl)
+			}
+		case c == ';':
+			statements = append(statements, sb.String())
+			sb.Reset()
+		case c == '\'' || c == '"' || c == '`':
+			quote = string(c)
+			sb.WriteByte(c)
+		case c == '$' && dialect == DIALECT_POSTGRES && dollarQuote.MatchString(rest):
+			quote = dollarQuote.FindString(rest)
+			sb.WriteString(quote)
+			i += len(quote) - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return append(statements, sb.String())
+}
+
+// splitSQL splits the statement on sep outside of parentheses and quotes.
+func splitSQL(statement string, sep rune) []string {
+	parts := make([]string, 0)
+	depth := 0
+	var quote rune
+	start := 0
+	for i, r := range statement {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, statement[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, statement[start:])
+}
+
+// parenthesized returns the content of the parentheses opening the statement.
+func parenthesized(statement string) (string, error) {
+	depth := 0
+	var quote rune
+	for i, r := range statement {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return statement[1:i], nil
+			}
+		}
+	}
+	return "", errors.New("unbalanced parentheses")
+}
+
+func splitSQLName(definition string) (string, string) {
+	if len(definition) > 0 && strings.ContainsRune("\"`[", rune(definition[0])) {
+		closing := map[byte]byte{'"': '"', '`': '`', '[': ']'}[definition[0]]
+		if end := strings.IndexByte(definition[1:], closing); end >= 0 {
+			return definition[1 : end+1], definition[end+2:]
+		}
+	}
+	end := strings.IndexFunc(definition, unicode.IsSpace)
+	if end < 0 {
+		return definition, ""
+	}
+	return definition[:end], definition[end:]
+}
+
+// unquoteSQLName removes the quotes and the schema of a table or column name.
+func unquoteSQLName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Trim(name, "\"`[]")
+}
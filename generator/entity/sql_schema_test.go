@@ -0,0 +1,264 @@
+package entity_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+const postgresDDL = `
+-- customers of the store
+CREATE TABLE IF NOT EXISTS public.customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    full_name VARCHAR(120) NOT NULL,
+    email text,
+    tags text[],
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CONSTRAINT customers_email_key UNIQUE (email)
+);
+
+CREATE INDEX customers_full_name_idx ON customers (full_name);
+`
+
+const mysqlDDL = "/* order lines */\n" +
+	"CREATE TABLE `order_items` (\n" +
+	"  `id` char(36) NOT NULL,\n" +
+	"  `quantity` int unsigned NOT NULL DEFAULT '1',\n" +
+	"  `price` decimal(10,2) DEFAULT NULL,\n" +
+	"  `active` tinyint(1) NOT NULL DEFAULT '1',\n" +
+	"  `note` varchar(255) COMMENT 'free, text',\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  KEY `idx_quantity` (`quantity`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n"
+
+func TestParseSQLSchemas(t *testing.T) {
+	t.Run("it should parse postgres tables", func(t *testing.T) {
+		actual, _, err := entity.ParseSQLSchemas(postgresDDL, entity.DIALECT_POSTGRES, entity.NULLABLE_POINTER)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []entity.EntitySchema{
+			{
+				Name: "Customer",
+				ID:   entity.SchemaID{Name: "ID", Tags: map[string]string{"db": "id", "json": "id"}},
+				Fields: []entity.SchemaField{
+					{Name: "FullName", Type: "string", Tags: map[string]string{"db": "full_name", "json": "full_name"}},
+					{Name: "Email", Type: "string", Optional: true, Tags: map[string]string{"db": "email", "json": "email,omitempty"}},
+					{Name: "Tags", Type: "[]string", Tags: map[string]string{"db": "tags", "json": "tags"}},
+					{Name: "Metadata", Type: "json.RawMessage", Tags: map[string]string{"db": "metadata", "json": "metadata"}},
+					{Name: "CreatedAt", Type: "time.Time", Tags: map[string]string{"db": "created_at", "json": "created_at"}},
+				},
+			},
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should parse mysql tables with sql null types", func(t *testing.T) {
+		actual, _, err := entity.ParseSQLSchemas(mysqlDDL, entity.DIALECT_MYSQL, entity.NULLABLE_SQL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []entity.EntitySchema{
+			{
+				Name: "OrderItem",
+				ID:   entity.SchemaID{Name: "ID", Tags: map[string]string{"db": "id", "json": "id"}},
+				Fields: []entity.SchemaField{
+					{Name: "Quantity", Type: "uint32", Tags: map[string]string{"db": "quantity", "json": "quantity"}},
+					{Name: "Price", Type: "sql.NullString", Tags: map[string]string{"db": "price", "json": "price"}},
+					{Name: "Active", Type: "bool", Tags: map[string]string{"db": "active", "json": "active"}},
+					{Name: "Note", Type: "sql.NullString", Tags: map[string]string{"db": "note", "json": "note"}},
+				},
+			},
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should skip the tables without a single uuid primary key", func(t *testing.T) {
+		ddl := "CREATE TABLE logs (message text);\n" +
+			"CREATE TABLE tags (a uuid, b uuid, PRIMARY KEY (a, b));\n" +
+			"CREATE TABLE users (id bigserial PRIMARY KEY);\n" +
+			"CREATE TABLE themes (id uuid PRIMARY KEY);\n"
+
+		schemas, skipped, err := entity.ParseSQLSchemas(ddl, entity.DIALECT_POSTGRES, entity.NULLABLE_POINTER)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(schemas) != 1 || schemas[0].Name != "Theme" {
+			utils.Error(t, "Theme", schemas)
+		}
+		want := []string{
+			"skipped table logs: no primary key",
+			"skipped table tags: composite primary key",
+			"skipped table users: primary key id is bigserial, only uuid, char(36), varchar(36) and binary(16) " +
+				"keys are supported as generated layers identify entities by uuid.UUID",
+		}
+		actual := make([]string, 0, len(skipped))
+		for _, err := range skipped {
+			actual = append(actual, err.Error())
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should widen the sql null types of the columns of other sizes", func(t *testing.T) {
+		ddl := "CREATE TABLE readings (\n" +
+			"  id uuid PRIMARY KEY,\n" +
+			"  level tinyint,\n" +
+			"  ratio real,\n" +
+			"  port smallint unsigned,\n" +
+			"  count int unsigned,\n" +
+			"  total bigint unsigned,\n" +
+			"  raw blob\n" +
+			");\n"
+
+		schemas, _, err := entity.ParseSQLSchemas(ddl, entity.DIALECT_MYSQL, entity.NULLABLE_SQL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{
+			"Level": "sql.NullInt16",
+			"Ratio": "sql.NullFloat64",
+			"Port":  "sql.NullInt32",
+			"Count": "sql.NullInt64",
+			"Total": "uint64",
+			"Raw":   "[]byte",
+		}
+		for _, field := range schemas[0].Fields {
+			if want[field.Name] != field.Type {
+				utils.Error(t, want[field.Name], field.Type)
+			}
+			if optional := field.Name == "Total"; optional != field.Optional {
+				utils.Error(t, optional, field.Optional)
+			}
+		}
+	})
+
+	t.Run("it should only strip the comments out of quotes", func(t *testing.T) {
+		type testCase struct {
+			it      string
+			ddl     string
+			dialect string
+		}
+
+		tc := []testCase{
+			{
+				it: "should keep # and -- in postgres strings and dollar-quoted bodies",
+				ddl: "CREATE TABLE themes (\n" +
+					"  id uuid PRIMARY KEY, -- the key\n" +
+					"  color text DEFAULT '#ffffff', /* hex; rgb */\n" +
+					"  note text DEFAULT 'a -- b'\n" +
+					");\n" +
+					"CREATE FUNCTION touch() RETURNS trigger AS $body$ BEGIN; CREATE TABLE x (y int); END; $body$ LANGUAGE plpgsql;\n",
+				dialect: entity.DIALECT_POSTGRES,
+			},
+			{
+				it: "should strip # comments in mysql and keep escaped quotes",
+				ddl: "# themes of the store\n" +
+					"CREATE TABLE `themes` (\n" +
+					"  `id` char(36) NOT NULL, # the key\n" +
+					"  `color` text DEFAULT '#ffffff',\n" +
+					"  `note` text DEFAULT 'it\\'s a -- b; c',\n" +
+					"  PRIMARY KEY (`id`)\n" +
+					");\n",
+				dialect: entity.DIALECT_MYSQL,
+			},
+		}
+
+		for _, c := range tc {
+			t.Run(c.it, func(t *testing.T) {
+				actual, _, err := entity.ParseSQLSchemas(c.ddl, c.dialect, entity.NULLABLE_POINTER)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := []entity.EntitySchema{
+					{
+						Name: "Theme",
+						ID:   entity.SchemaID{Name: "ID", Tags: map[string]string{"db": "id", "json": "id"}},
+						Fields: []entity.SchemaField{
+							{Name: "Color", Type: "string", Optional: true, Tags: map[string]string{"db": "color", "json": "color,omitempty"}},
+							{Name: "Note", Type: "string", Optional: true, Tags: map[string]string{"db": "note", "json": "note,omitempty"}},
+						},
+					},
+				}
+				if !reflect.DeepEqual(want, actual) {
+					utils.Error(t, want, actual)
+				}
+			})
+		}
+	})
+
+	t.Run("it should generate compilable entities", func(t *testing.T) {
+		schemas, _, err := entity.ParseSQLSchemas(mysqlDDL, entity.DIALECT_MYSQL, entity.NULLABLE_SQL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := entity.NewEntity(schemas[0], "src/structs", "github.com/eduardoths/microservice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "package structs\n\n" +
+			"import (\n" +
+			"\t\"database/sql\"\n" +
+			"\t\"github.com/google/uuid\"\n" +
+			")\n\n" +
+			"type OrderItem struct {\n" +
+			"\tID uuid.UUID `json:\"id\" db:\"id\"`\n" +
+			"\tQuantity uint32 `json:\"quantity\" db:\"quantity\"`\n" +
+			"\tPrice sql.NullString `json:\"price\" db:\"price\"`\n" +
+			"\tActive bool `json:\"active\" db:\"active\"`\n" +
+			"\tNote sql.NullString `json:\"note\" db:\"note\"`\n" +
+			"}\n"
+		if actual := e.File().String(); want != actual {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should fail for invalid scripts", func(t *testing.T) {
+		type testCase struct {
+			it      string
+			ddl     string
+			wantErr error
+		}
+
+		tc := []testCase{
+			{
+				it:      "should fail without tables",
+				ddl:     "CREATE INDEX idx ON customers (email);",
+				wantErr: entity.ErrInvalidSQL,
+			},
+			{
+				it:      "should fail when no table has a primary key",
+				ddl:     "CREATE TABLE logs (message text);",
+				wantErr: entity.ErrInvalidSQL,
+			},
+			{
+				it:      "should fail when every table has a composite or non uuid primary key",
+				ddl:     "CREATE TABLE tags (a uuid, b uuid, PRIMARY KEY (a, b)); CREATE TABLE users (id bigserial PRIMARY KEY);",
+				wantErr: entity.ErrInvalidSQL,
+			},
+			{
+				it:      "should fail with unknown column types",
+				ddl:     "CREATE TABLE places (id uuid PRIMARY KEY, location geometry);",
+				wantErr: entity.ErrUnsupportedSQLType,
+			},
+		}
+
+		for _, c := range tc {
+			t.Run(c.it, func(t *testing.T) {
+				_, _, err := entity.ParseSQLSchemas(c.ddl, entity.DIALECT_POSTGRES, entity.NULLABLE_POINTER)
+				if !errors.Is(err, c.wantErr) {
+					utils.Error(t, c.wantErr, err)
+				}
+			})
+		}
+	})
+}
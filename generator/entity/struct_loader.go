@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+var ErrStructNotFound = errors.New("struct not found")
+
+// LoadStruct reads the entity struct declaration from the Go files found in
+// the entity directory under root.
+func LoadStruct(root string, en EntityName) (file.Struct, error) {
+	dir := filepath.Join(root, en.dirPath)
+	files, err := parseDir(dir)
+	if err != nil {
+		return file.Struct{}, err
+	}
+	for _, f := range files {
+		if s, ok := findStruct(f, en.PascalCase()); ok {
+			return s, nil
+		}
+	}
+	return file.Struct{}, fmt.Errorf("%w: %s in %s", ErrStructNotFound, en.PascalCase(), dir)
+}
+
+// LoadStructs reads every exported struct declared in the Go files of dirPath
+// under root, sorted by name.
+func LoadStructs(root string, dirPath string) ([]file.Struct, error) {
+	files, err := parseDir(filepath.Join(root, dirPath))
+	if err != nil {
+		return nil, err
+	}
+
+	structs := make([]file.Struct, 0)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok || !typeSpec.Name.IsExported() {
+					continue
+				}
+				structs = append(structs, file.Struct{Name: typeSpec.Name.Name, Fields: structFields(structType)})
+			}
+		}
+	}
+	sort.Slice(structs, func(i, j int) bool { return structs[i].Name < structs[j].Name })
+	return structs, nil
+}
+
+func parseDir(dir string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func findStruct(f *ast.File, name string) (file.Struct, bool) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok || typeSpec.Name.Name != name {
+				continue
+			}
+			return file.Struct{Name: name, Fields: structFields(structType)}, true
+		}
+	}
+	return file.Struct{}, false
+}
+
+func structFields(structType *ast.StructType) []file.Field {
+	fields := make([]file.Field, 0, len(structType.Fields.List))
+	for _, field := range structType.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag = field.Tag.Value
+		}
+		typ := types.ExprString(field.Type)
+		if len(field.Names) == 0 {
+			fields = append(fields, file.Field{Name: typ, Tag: tag})
+			continue
+		}
+		for _, name := range field.Names {
+			fields = append(fields, file.Field{Name: name.Name, Type: typ, Tag: tag})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,77 @@
+package entity_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestLoadStruct(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "src", "structs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := "package structs\n\n" +
+		"import (\n\t\"time\"\n\n\t\"github.com/google/uuid\"\n)\n\n" +
+		"type Base struct{}\n\n" +
+		"type XptoStruct struct {\n" +
+		"\tBase\n" +
+		"\tID uuid.UUID `json:\"id\"`\n" +
+		"\tFirst, Last string\n" +
+		"\tCreatedAt time.Time\n" +
+		"\tTags []string `json:\"tags,omitempty\"`\n" +
+		"}\n"
+	if err := os.WriteFile(filepath.Join(dir, "xpto_struct.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("it should load the struct fields", func(t *testing.T) {
+		actual, err := entity.LoadStruct(root, entity.NewEntityName("XptoStruct", "src/structs", "github.com/eduardoths/microservice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := file.Struct{
+			Name: "XptoStruct",
+			Fields: []file.Field{
+				{Name: "Base"},
+				{Name: "ID", Type: "uuid.UUID", Tag: "`json:\"id\"`"},
+				{Name: "First", Type: "string"},
+				{Name: "Last", Type: "string"},
+				{Name: "CreatedAt", Type: "time.Time"},
+				{Name: "Tags", Type: "[]string", Tag: "`json:\"tags,omitempty\"`"},
+			},
+		}
+		if !reflect.DeepEqual(want, actual) {
+			utils.Error(t, want, actual)
+		}
+	})
+
+	t.Run("it should fail when the struct does not exist", func(t *testing.T) {
+		_, err := entity.LoadStruct(root, entity.NewEntityName("Missing", "src/structs", "github.com/eduardoths/microservice"))
+		if !errors.Is(err, entity.ErrStructNotFound) {
+			utils.Error(t, entity.ErrStructNotFound, err)
+		}
+	})
+
+	t.Run("it should load every exported struct sorted by name", func(t *testing.T) {
+		actual, err := entity.LoadStructs(root, "src/structs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, 0, len(actual))
+		for _, s := range actual {
+			names = append(names, s.Name)
+		}
+		want := []string{"Base", "XptoStruct"}
+		if !reflect.DeepEqual(want, names) {
+			utils.Error(t, want, names)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+const (
+	NAMING_SNAKE = "snake"
+	NAMING_CAMEL = "camel"
+	NAMING_KEBAB = "kebab"
+
+	JSON_TAG     = "json"
+	VALIDATE_TAG = "validate"
+	REQUIRED_TAG = "required"
+)
+
+var ErrInvalidTagNaming = errors.New("invalid tag naming")
+
+// omitEmptyTags are the keys whose values get the omitempty option on the
+// optional fields.
+var omitEmptyTags = map[string]bool{"json": true, "yaml": true, "xml": true}
+
+// TagNaming maps the keys of the tags populated on every entity field to the
+// naming strategy of their values. The validate key is populated with the
+// required rule on the required fields instead. It is read from the --tag
+// flags only, projects have no configuration file for it.
+type TagNaming map[string]string
+
+func DefaultTagNaming() TagNaming {
+	return TagNaming{JSON_TAG: NAMING_SNAKE}
+}
+
+func TagNamings() []string {
+	return []string{NAMING_CAMEL, NAMING_KEBAB, NAMING_SNAKE}
+}
+
+// ParseTagNaming parses a tag key to populate along with its naming
+// strategy, as key[=strategy], snake by default.
+func ParseTagNaming(raw string) (string, string, error) {
+	key, strategy, found := strings.Cut(raw, "=")
+	if !found {
+		strategy = NAMING_SNAKE
+	}
+	if key == "" || strings.ContainsAny(key, " :\"`") {
+		return "", "", fmt.Errorf("%w: %q, expected key[=strategy]", ErrInvalidTagNaming, raw)
+	}
+	for _, naming := range TagNamings() {
+		if strategy == naming {
+			return key, strategy, nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: %q, strategy must be one of %s", ErrInvalidTagNaming, raw, strings.Join(TagNamings(), ", "))
+}
+
+// Tag returns the tag of the field name, the explicit tags taking precedence
+// over the populated ones. The json key goes first and the others are
+// sorted. Optional fields get the omitempty option and required ones the
+// required rule, see requirable.
+func (n TagNaming) Tag(name EntityName, optional bool, required bool, explicit map[string]string) (file.Tag, error) {
+	keys := make([]string, 0, len(n)+len(explicit))
+	seen := make(map[string]bool, len(n)+len(explicit))
+	for _, tags := range []map[string]string{n, explicit} {
+		for key := range tags {
+			if !seen[key] && key != JSON_TAG {
+				keys = append(keys, key)
+			}
+			seen[key] = true
+		}
+	}
+	sort.Strings(keys)
+	if seen[JSON_TAG] {
+		keys = append([]string{JSON_TAG}, keys...)
+	}
+
+	tag := file.Tag{}
+	for _, key := range keys {
+		if value, ok := explicit[key]; ok {
+			parts := strings.Split(value, ",")
+			tag = tag.Set(key, parts[0], parts[1:]...)
+			continue
+		}
+		if key == VALIDATE_TAG {
+			if required {
+				tag = tag.Set(key, REQUIRED_TAG)
+			}
+			continue
+		}
+		var options []string
+		if optional && omitEmptyTags[key] {
+			options = append(options, "omitempty")
+		}
+		tag = tag.Set(key, n.name(key, name), options...)
+	}
+	if err := tag.Validate(); err != nil {
+		return nil, fmt.Errorf("field %s: %w", name.PascalCase(), err)
+	}
+	return tag, nil
+}
+
+// requirable reports whether the required rule of validate fits the Go
+// type: strings, slices, maps and pointers, whose zero value stands for a
+// missing one. Numbers, booleans and structs are left out, as their zero
+// value is a valid one the rule would reject.
+func requirable(goType string) bool {
+	return goType == "string" || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") ||
+		strings.HasPrefix(goType, "*")
+}
+
+// name returns the name of the field in the values of key.
+func (n TagNaming) name(key string, name EntityName) string {
+	switch n[key] {
+	case NAMING_CAMEL:
+		// built from the snake case so that initialisms are lowered too,
+		// as in customerId
+		return NewEntityName(name.SnakeCase(), "", "").CamelCase()
+	case NAMING_KEBAB:
+		return name.KebabCase()
+	default:
+		return name.SnakeCase()
+	}
+}
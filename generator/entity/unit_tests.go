@@ -0,0 +1,168 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+// methodTestCase is a case of the table-driven tests generated for the
+// repositories and services, calling one method of the layer.
+type methodTestCase struct {
+	it      string
+	method  file.Method
+	err     string
+	wantErr string
+}
+
+// methodTestWords returns the words describing the call of the method in
+// the name of its test cases, and the entity or entities it works on.
+func methodTestWords(structName EntityName, m file.Method) (string, string) {
+	words := strings.ReplaceAll(NewEntityName(m.Name, "", "").SnakeCase(), "_", " ")
+	if m.Name == "GetAll" {
+		return words, strings.ReplaceAll(structName.Plural().SnakeCase(), "_", " ")
+	}
+	return words, strings.ReplaceAll(structName.SnakeCase(), "_", " ")
+}
+
+func hasParam(m file.Method, paramType string) bool {
+	for _, param := range m.Params {
+		if param.Type == paramType {
+			return true
+		}
+	}
+	return false
+}
+
+// methodTestBody returns the body of a table-driven test calling the
+// methods of the interface iface through the cases. The lines of run assign
+// the error returned by c.call to err, the error of the case being c.err.
+func methodTestBody(iface string, subject string, cases []methodTestCase, setup []string, run []string) []string {
+	lines := []string{
+		"ctx := context.Background()",
+		"id := uuid.New()",
+	}
+	lines = append(lines, setup...)
+	lines = append(lines,
+		"",
+		"type testCase struct {",
+		"\tit      string",
+		"\tcall    func("+subject+" "+iface+") error",
+	)
+	for _, c := range cases {
+		if c.err != "" {
+			lines = append(lines, "\terr     error")
+			break
+		}
+	}
+	lines = append(lines,
+		"\twantErr error",
+		"}",
+		"",
+		"tc := []testCase{",
+	)
+	for _, c := range cases {
+		lines = append(lines,
+			"\t{",
+			"\t\tit: "+strconv.Quote(c.it)+",",
+			"\t\tcall: func("+subject+" "+iface+") error {",
+		)
+		for _, line := range callLines(subject, c.method) {
+			lines = append(lines, "\t\t\t"+line)
+		}
+		lines = append(lines, "\t\t},")
+		if c.err != "" {
+			lines = append(lines, "\t\terr: "+c.err+",")
+		}
+		if c.wantErr != "" {
+			lines = append(lines, "\t\twantErr: "+c.wantErr+",")
+		}
+		lines = append(lines, "\t},")
+	}
+	lines = append(lines,
+		"}",
+		"",
+		"for _, c := range tc {",
+		"\tt.Run(c.it, func(t *testing.T) {",
+	)
+	for _, line := range run {
+		lines = append(lines, "\t\t"+line)
+	}
+	lines = append(lines,
+		"\t\tif !errors.Is(err, c.wantErr) {",
+		"\t\t\t"+`t.Errorf("got error %v, want %v", err, c.wantErr)`,
+		"\t\t}",
+		"\t})",
+		"}",
+	)
+	return lines
+}
+
+// callLines returns the lines calling the method on subject with the test
+// arguments, none for its variadic param, and returning its error.
+func callLines(subject string, m file.Method) []string {
+	args := make([]string, 0, len(m.Params))
+	for _, param := range m.Params {
+		if param.Variadic {
+			continue
+		}
+		switch param.Type {
+		case CONTEXT_TYPE:
+			args = append(args, "ctx")
+		case ID_TYPE:
+			args = append(args, "id")
+		default:
+			args = append(args, param.Type+"{}")
+		}
+	}
+	call := subject + "." + m.Name + "(" + strings.Join(args, ", ") + ")"
+	if len(m.Results) == 1 {
+		return []string{"return " + call}
+	}
+
+	results := make([]string, 0, len(m.Results))
+	for _, result := range m.Results {
+		if result.Type == "error" {
+			results = append(results, "err")
+		} else {
+			results = append(results, "_")
+		}
+	}
+	return []string{
+		strings.Join(results, ", ") + " := " + call,
+		"return err",
+	}
+}
+
+// fake returns an in-memory implementation of the methods, returning the
+// zero value of their results along with the error it holds.
+func fake(name EntityName, methods []file.Method) file.Struct {
+	s := file.Struct{
+		Name:   name.CamelCase(),
+		Fields: []file.Field{{Name: "err", Type: "error"}},
+	}
+	alias := receiverAlias(name, methods, nil)
+	for _, m := range methods {
+		results := make([]string, 0, len(m.Results))
+		for _, result := range m.Results {
+			if result.Type == "error" {
+				results = append(results, alias+".err")
+			} else {
+				results = append(results, result.Name)
+			}
+		}
+		s.Implementations = append(s.Implementations, file.Implementation{
+			StructAlias: alias,
+			StructName:  s.Name,
+			Func:        m,
+			CodeLines:   []string{"return " + strings.Join(results, ", ")},
+		})
+	}
+	return s
+}
+
+// testFilePath returns the path of the tests of the file at path.
+func testFilePath(path string) string {
+	return strings.TrimSuffix(path, ".go") + "_test.go"
+}
@@ -1,23 +1,57 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 )
 
+var ErrMisplacedVariadic = errors.New("only the last param can be variadic")
+
 type File struct {
-	Package    string
-	Imports    Imports
-	Funcs      []Implementation
-	Interfaces []Interface
-	Structs    []Struct
+	Package    string           `json:"package"`
+	Imports    Imports          `json:"imports,omitempty"`
+	Vars       []Var            `json:"vars,omitempty"`
+	Types      []Type           `json:"types,omitempty"`
+	Funcs      []Implementation `json:"funcs,omitempty"`
+	Interfaces []Interface      `json:"interfaces,omitempty"`
+	Structs    []Struct         `json:"structs,omitempty"`
+}
+
+// Validate checks the signatures of the functions, methods and interfaces
+// of the file.
+func (f File) Validate() error {
+	methods := make([]Method, 0)
+	for _, impl := range f.Funcs {
+		methods = append(methods, impl.Func)
+	}
+	for _, s := range f.Structs {
+		for _, impl := range s.Implementations {
+			methods = append(methods, impl.Func)
+		}
+	}
+	for _, iface := range f.Interfaces {
+		methods = append(methods, iface.MethodSet()...)
+	}
+	for _, m := range methods {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (f File) String() string {
 	var sb strings.Builder
 	sb.WriteString("package " + f.Package + "\n")
-	sb.WriteString(f.Imports.String())
+	sb.WriteString(f.AllImports().String())
+	for i := range f.Vars {
+		sb.WriteString(f.Vars[i].String())
+	}
+	for i := range f.Types {
+		sb.WriteString(f.Types[i].String())
+	}
 	for i := range f.Funcs {
 		sb.WriteString(f.Funcs[i].String())
 	}
@@ -30,6 +64,21 @@ func (f File) String() string {
 	return sb.String()
 }
 
+// AllImports returns the imports of the file along with the ones referenced
+// by the bodies of its functions and methods.
+func (f File) AllImports() Imports {
+	imports := append(Imports(nil), f.Imports...)
+	for _, fn := range f.Funcs {
+		imports = append(imports, fn.Body.Imports()...)
+	}
+	for _, s := range f.Structs {
+		for _, impl := range s.Implementations {
+			imports = append(imports, impl.Body.Imports()...)
+		}
+	}
+	return imports
+}
+
 type Imports []Import
 
 func (imports Imports) String() string {
@@ -70,8 +119,8 @@ func (x Imports) Less(i, j int) bool { return x[i].Path < x[j].Path }
 func (x Imports) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
 
 type Import struct {
-	Path string
-	Name string
+	Path string `json:"path"`
+	Name string `json:"name,omitempty"`
 }
 
 func (i Import) String() string {
@@ -86,17 +135,67 @@ func (i Import) String() string {
 	return sb.String()
 }
 
+// PackageName returns the name the import is referred by, assuming packages
+// are named after the last element of their path that is not a major version.
+func (i Import) PackageName() string {
+	if i.Name != "" {
+		return i.Name
+	}
+	elems := strings.Split(i.Path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && strings.HasPrefix(name, "v") && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	return strings.NewReplacer("-", "", ".", "").Replace(strings.TrimPrefix(name, "go-"))
+}
+
+type Var struct {
+	Name  string `json:"name"`
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+func (v Var) String() string {
+	var sb strings.Builder
+	sb.WriteString("\nvar " + v.Name)
+	if v.Type != "" {
+		sb.WriteString(" " + v.Type)
+	}
+	if v.Value != "" {
+		sb.WriteString(" = " + v.Value)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// Type declares a named type that is neither an interface nor a struct, as
+// in type Option func(*server).
+type Type struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (t Type) String() string {
+	return "\ntype " + t.Name + " " + t.Type + "\n"
+}
+
 type Interface struct {
-	Name    string
-	Methods []Method
+	Name string `json:"name"`
+	// Embeds are the interfaces embedded by this one, referred by their
+	// name, e.g. io.Closer, and declaring the methods they bring.
+	Embeds  []Interface `json:"embeds,omitempty"`
+	Methods []Method    `json:"methods,omitempty"`
 }
 
 func (i Interface) String() string {
 	var sb strings.Builder
 	sb.WriteString("\ntype " + i.Name + " interface {")
-	if len(i.Methods) > 0 {
+	if len(i.Embeds) > 0 || len(i.Methods) > 0 {
 		sb.WriteString("\n")
 	}
+	for j := range i.Embeds {
+		sb.WriteString("\t" + i.Embeds[j].Name + "\n")
+	}
 	for j := range i.Methods {
 		sb.WriteString("\t" + i.Methods[j].String() + "\n")
 	}
@@ -104,24 +203,77 @@ func (i Interface) String() string {
 	return sb.String()
 }
 
+// MethodSet returns the methods of the interface followed by the ones of the
+// interfaces it embeds, each name once.
+func (i Interface) MethodSet() []Method {
+	methods := make([]Method, 0, len(i.Methods))
+	seen := make(map[string]bool)
+	var add func(iface Interface)
+	add = func(iface Interface) {
+		for _, m := range iface.Methods {
+			if !seen[m.Name] {
+				seen[m.Name] = true
+				methods = append(methods, m)
+			}
+		}
+		for _, embedded := range iface.Embeds {
+			add(embedded)
+		}
+	}
+	add(i)
+	return methods
+}
+
 type Method struct {
-	Name    string
-	Params  Args
-	Results Args
+	Name    string `json:"name"`
+	Params  Args   `json:"params,omitempty"`
+	Results Args   `json:"results,omitempty"`
+	// Grouped renders the consecutive params and results of the same type
+	// as one group, as in (a, b int).
+	Grouped bool `json:"grouped,omitempty"`
 }
 
 func (m Method) String() string {
+	return m.Name + m.signature()
+}
+
+// Validate checks that only the last of the params is variadic.
+func (m Method) Validate() error {
+	for i, param := range m.Params {
+		if param.Variadic && i != len(m.Params)-1 {
+			return fmt.Errorf("%w: %s of %s", ErrMisplacedVariadic, param.String(), m.Name)
+		}
+	}
+	for _, result := range m.Results {
+		if result.Variadic {
+			return fmt.Errorf("%w: result %s of %s", ErrMisplacedVariadic, result.String(), m.Name)
+		}
+	}
+	return nil
+}
+
+// FuncType returns the type of the method as a func value, as in
+// func(ctx context.Context) error, for func-typed args and fields.
+func (m Method) FuncType() string {
+	return "func" + m.signature()
+}
+
+func (m Method) signature() string {
+	render := Args.String
+	if m.Grouped {
+		render = Args.grouped
+	}
+
 	var sb strings.Builder
-	sb.WriteString(m.Name)
 	sb.WriteString("(")
-	sb.WriteString(m.Params.String())
+	sb.WriteString(render(m.Params))
 	sb.WriteString(")")
 	if len(m.Results) > 0 {
 		sb.WriteString(" ")
 		if m.Results[0].Name != "" || len(m.Results) > 1 {
 			sb.WriteString("(")
 		}
-		sb.WriteString(m.Results.String())
+		sb.WriteString(render(m.Results))
 		if m.Results[0].Name != "" || len(m.Results) > 1 {
 			sb.WriteString(")")
 		}
@@ -142,22 +294,49 @@ func (a Args) String() string {
 	return sb.String()
 }
 
+// grouped renders the args like String, the names of the consecutive args of
+// the same type sharing it.
+func (a Args) grouped() string {
+	var sb strings.Builder
+	for i, arg := range a {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		if arg.Name != "" && i+1 < len(a) && a[i+1].Name != "" && a[i+1].typeString() == arg.typeString() {
+			sb.WriteString(arg.Name)
+			continue
+		}
+		sb.WriteString(arg.String())
+	}
+	return sb.String()
+}
+
 type Arg struct {
-	Name string
-	Type string
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+	// Variadic makes the arg, the last one of the params, take any number
+	// of values of Type.
+	Variadic bool `json:"variadic,omitempty"`
 }
 
 func (a Arg) String() string {
 	if a.Name == "" {
-		return a.Type
+		return a.typeString()
+	}
+	return fmt.Sprintf("%s %s", a.Name, a.typeString())
+}
+
+func (a Arg) typeString() string {
+	if a.Variadic {
+		return "..." + a.Type
 	}
-	return fmt.Sprintf("%s %s", a.Name, a.Type)
+	return a.Type
 }
 
 type Struct struct {
-	Name            string
-	Fields          []Field
-	Implementations []Implementation
+	Name            string           `json:"name"`
+	Fields          []Field          `json:"fields,omitempty"`
+	Implementations []Implementation `json:"implementations,omitempty"`
 }
 
 func (s Struct) String() string {
@@ -182,16 +361,28 @@ func (s Struct) String() string {
 }
 
 type Field struct {
-	Name string
-	Type string
-	Tag  string
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+	// Embedded embeds Type in the struct, Name being ignored. A field of
+	// Name without Type is embedded too.
+	Embedded bool `json:"embedded,omitempty"`
+}
+
+// Embed returns the field embedding the type, e.g. sync.Mutex.
+func Embed(typ string) Field {
+	return Field{Type: typ, Embedded: true}
 }
 
 func (f Field) String() string {
 	var sb strings.Builder
 	sb.WriteString("\t")
-	sb.WriteString(f.Name)
-	if f.Type != "" {
+	if f.Embedded {
+		sb.WriteString(f.Type)
+	} else {
+		sb.WriteString(f.Name)
+	}
+	if f.Type != "" && !f.Embedded {
 		sb.WriteString(" ")
 		sb.WriteString(f.Type)
 	}
@@ -204,10 +395,38 @@ func (f Field) String() string {
 }
 
 type Implementation struct {
-	StructAlias string
-	StructName  string
-	Func        Method
-	CodeLines   []string
+	StructAlias string   `json:"structAlias,omitempty"`
+	StructName  string   `json:"structName,omitempty"`
+	Func        Method   `json:"func"`
+	CodeLines   []string `json:"codeLines,omitempty"`
+	// Body holds the statements following CodeLines, which the JSON model
+	// carries as code lines.
+	Body Block `json:"-"`
+	// UserRegion wraps the body in region markers so that regenerating the
+	// file preserves the code written in it.
+	UserRegion bool `json:"userRegion,omitempty"`
+}
+
+const (
+	REGION_BEGIN = "// microcli:begin "
+	REGION_END   = "// microcli:end"
+)
+
+// RegionKey identifies the body region of the implementation in its file.
+func (i Implementation) RegionKey() string {
+	if i.StructName == "" {
+		return i.Func.Name
+	}
+	return strings.TrimPrefix(i.StructName, "*") + "." + i.Func.Name
+}
+
+// Lines returns the lines of the body, CodeLines followed by the ones of
+// Body.
+func (i Implementation) Lines() []string {
+	if len(i.Body) == 0 {
+		return i.CodeLines
+	}
+	return append(append([]string(nil), i.CodeLines...), i.Body.Lines()...)
 }
 
 func (i Implementation) String() string {
@@ -223,11 +442,17 @@ func (i Implementation) String() string {
 	}
 	sb.WriteString(i.Func.String())
 	sb.WriteString(" {\n")
-	for j := range i.CodeLines {
+	if i.UserRegion {
+		sb.WriteString("\t" + REGION_BEGIN + i.RegionKey() + "\n")
+	}
+	for _, line := range i.Lines() {
 		sb.WriteString("\t")
-		sb.WriteString(i.CodeLines[j])
+		sb.WriteString(line)
 		sb.WriteString("\n")
 	}
+	if i.UserRegion {
+		sb.WriteString("\t" + REGION_END + "\n")
+	}
 	sb.WriteString("}\n")
 	return sb.String()
 }
@@ -123,6 +123,43 @@ func TestFile_String(t *testing.T) {
 				"\tfmt.Printf(\"Hello, %s!\", name)\n" +
 				"}\n",
 		},
+		{
+			it: "should return a file with a typed var",
+			file: file.File{
+				Package: "test",
+				Vars: []file.Var{
+					{Name: "_", Type: "Xpto", Value: "(*xpto)(nil)"},
+				},
+			},
+			want: "package test\n\n" +
+				"var _ Xpto = (*xpto)(nil)\n",
+		},
+		{
+			it: "should return a file with an untyped var",
+			file: file.File{
+				Package: "test",
+				Imports: file.Imports{
+					{Path: "errors"},
+				},
+				Vars: []file.Var{
+					{Name: "ErrNotFound", Value: "errors.New(\"not found\")"},
+				},
+			},
+			want: "package test\n\n" +
+				"import \"errors\"\n\n" +
+				"var ErrNotFound = errors.New(\"not found\")\n",
+		},
+		{
+			it: "should return a file with a named type after the vars",
+			file: file.File{
+				Package: "test",
+				Vars:    []file.Var{{Name: "_", Type: "Xpto", Value: "(*xpto)(nil)"}},
+				Types:   []file.Type{{Name: "XptoOption", Type: "func(*xpto)"}},
+			},
+			want: "package test\n\n" +
+				"var _ Xpto = (*xpto)(nil)\n\n" +
+				"type XptoOption func(*xpto)\n",
+		},
 		{
 			it: "should return a file with an empty interface",
 			file: file.File{
@@ -511,6 +548,33 @@ func TestFile_String(t *testing.T) {
 				"\tpanic(\"Ovo da panico\")\n" +
 				"}\n",
 		},
+		{
+			it: "should return a file with an implementation body in a user region",
+			file: file.File{
+				Package: "structs",
+				Structs: []file.Struct{
+					{
+						Name: "Xpto",
+						Implementations: []file.Implementation{
+							{
+								StructAlias: "x",
+								StructName:  "*Xpto",
+								Func:        file.Method{Name: "Foo"},
+								CodeLines:   []string{"panic(\"not implemented\")"},
+								UserRegion:  true,
+							},
+						},
+					},
+				},
+			},
+			want: "package structs\n\n" +
+				"type Xpto struct {}\n\n" +
+				"func (x *Xpto) Foo() {\n" +
+				"\t// microcli:begin Xpto.Foo\n" +
+				"\tpanic(\"not implemented\")\n" +
+				"\t// microcli:end\n" +
+				"}\n",
+		},
 		{
 			it: "should return a file with a struct with two implementations",
 			file: file.File{
@@ -661,6 +725,76 @@ func TestFile_String(t *testing.T) {
 				"}\n\n" +
 				"type emptyStruct struct {}\n",
 		},
+		{
+			it: "should return variadic, grouped and func-typed params",
+			file: file.File{
+				Package: "test",
+				Interfaces: []file.Interface{
+					{
+						Name: "Xpto",
+						Methods: []file.Method{
+							{
+								Name:    "List",
+								Params:  file.Args{{Name: "ctx", Type: "context.Context"}, {Name: "opts", Type: "Option", Variadic: true}},
+								Results: file.Args{{Type: "error"}},
+							},
+							{
+								Name:    "Move",
+								Params:  file.Args{{Name: "x", Type: "int"}, {Name: "y", Type: "int"}, {Name: "name", Type: "string"}},
+								Results: file.Args{{Name: "dx", Type: "int"}, {Name: "dy", Type: "int"}},
+								Grouped: true,
+							},
+							{
+								Name: "Each",
+								Params: file.Args{{Name: "fn", Type: file.Method{
+									Params:  file.Args{{Type: "int"}},
+									Results: file.Args{{Type: "error"}},
+								}.FuncType()}},
+							},
+						},
+					},
+				},
+			},
+			want: "package test\n\n" +
+				"type Xpto interface {\n" +
+				"\tList(ctx context.Context, opts ...Option) error\n" +
+				"\tMove(x, y int, name string) (dx, dy int)\n" +
+				"\tEach(fn func(int) error)\n" +
+				"}\n",
+		},
+		{
+			it: "should return embedded interfaces and struct fields",
+			file: file.File{
+				Package: "test",
+				Interfaces: []file.Interface{
+					{
+						Name:    "Repository",
+						Embeds:  []file.Interface{{Name: "io.Closer"}},
+						Methods: []file.Method{{Name: "Ping", Results: file.Args{{Type: "error"}}}},
+					},
+				},
+				Structs: []file.Struct{
+					{
+						Name: "repository",
+						Fields: []file.Field{
+							file.Embed("sync.Mutex"),
+							{Name: "ignored", Type: "*Base", Tag: "`json:\"base\"`", Embedded: true},
+							{Name: "db", Type: "*sql.DB"},
+						},
+					},
+				},
+			},
+			want: "package test\n\n" +
+				"type Repository interface {\n" +
+				"\tio.Closer\n" +
+				"\tPing() error\n" +
+				"}\n\n" +
+				"type repository struct {\n" +
+				"\tsync.Mutex\n" +
+				"\t*Base `json:\"base\"`\n" +
+				"\tdb *sql.DB\n" +
+				"}\n",
+		},
 	}
 
 	for _, c := range tc {
@@ -674,3 +808,25 @@ func TestFile_String(t *testing.T) {
 		})
 	}
 }
+
+func TestInterface_MethodSet(t *testing.T) {
+	closeMethod := file.Method{Name: "Close", Results: file.Args{{Type: "error"}}}
+	iface := file.Interface{
+		Name: "Repository",
+		Embeds: []file.Interface{
+			{Name: "io.Closer", Methods: []file.Method{closeMethod}},
+			{Name: "Pinger", Embeds: []file.Interface{{Name: "io.Closer", Methods: []file.Method{closeMethod}}}, Methods: []file.Method{{Name: "Ping"}}},
+		},
+		Methods: []file.Method{{Name: "Get"}},
+	}
+
+	t.Run("should return the methods of the interface and of the embedded ones once", func(t *testing.T) {
+		names := make([]string, 0)
+		for _, m := range iface.MethodSet() {
+			names = append(names, m.Name)
+		}
+		if got, want := fmt.Sprint(names), "[Get Close Ping]"; got != want {
+			t.Errorf("Interface.MethodSet() failed, want: %s got %s", want, got)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SCHEMA_VERSION is the version of the JSON model of the files, increased on
+// breaking changes only.
+const SCHEMA_VERSION = 1
+
+var ErrUnsupportedSchema = errors.New("unsupported file model schema")
+
+// fileFields has the fields of File without its JSON methods.
+type fileFields File
+
+type fileJSON struct {
+	Version int `json:"version"`
+	fileFields
+}
+
+// MarshalJSON encodes the file along with the version of its schema. The
+// bodies built as statements are encoded as code lines, and the imports they
+// reference as imports of the file.
+func (f File) MarshalJSON() ([]byte, error) {
+	flat := f
+	flat.Imports = f.AllImports()
+	flat.Funcs = flatten(f.Funcs)
+	flat.Structs = make([]Struct, 0, len(f.Structs))
+	for _, s := range f.Structs {
+		s.Implementations = flatten(s.Implementations)
+		flat.Structs = append(flat.Structs, s)
+	}
+	return json.Marshal(fileJSON{Version: SCHEMA_VERSION, fileFields: fileFields(flat)})
+}
+
+// flatten returns the implementations with their Body moved to CodeLines.
+func flatten(impls []Implementation) []Implementation {
+	if impls == nil {
+		return nil
+	}
+	flat := make([]Implementation, 0, len(impls))
+	for _, impl := range impls {
+		impl.CodeLines = impl.Lines()
+		impl.Body = nil
+		flat = append(flat, impl)
+	}
+	return flat
+}
+
+// UnmarshalJSON decodes a file of the current schema version, rejecting the
+// fields it does not know so that misspelled ones are not silently dropped,
+// and the signatures that would not compile.
+func (f *File) UnmarshalJSON(data []byte) error {
+	var model fileJSON
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&model); err != nil {
+		return err
+	}
+	if model.Version != SCHEMA_VERSION {
+		return fmt.Errorf("%w: version %d, expected %d", ErrUnsupportedSchema, model.Version, SCHEMA_VERSION)
+	}
+	if err := File(model.fileFields).Validate(); err != nil {
+		return err
+	}
+	*f = File(model.fileFields)
+	return nil
+}
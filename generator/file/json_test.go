@@ -0,0 +1,122 @@
+package file_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+var jsonFile = file.File{
+	Package: "repository",
+	Imports: file.Imports{{Path: "context"}, {Path: "github.com/e/svc/src/structs", Name: "entities"}},
+	Vars:    []file.Var{{Name: "ErrNotFound", Value: `errors.New("not found")`}},
+	Interfaces: []file.Interface{{
+		Name: "XptoRepository",
+		Methods: []file.Method{{
+			Name:    "Get",
+			Params:  file.Args{{Name: "ctx", Type: "context.Context"}},
+			Results: file.Args{{Type: "entities.Xpto"}, {Type: "error"}},
+		}},
+	}},
+	Structs: []file.Struct{{
+		Name:   "xptoRepository",
+		Fields: []file.Field{{Name: "db", Type: "*sql.DB", Tag: "`json:\"-\"`"}},
+		Implementations: []file.Implementation{{
+			StructAlias: "xr",
+			StructName:  "*xptoRepository",
+			Func: file.Method{
+				Name:    "Get",
+				Params:  file.Args{{Name: "ctx", Type: "context.Context"}},
+				Results: file.Args{{Type: "entities.Xpto"}, {Type: "error"}},
+			},
+			CodeLines:  []string{`panic("not implemented")`},
+			UserRegion: true,
+		}},
+	}},
+}
+
+func TestFile_MarshalJSON(t *testing.T) {
+	t.Run("should encode the file with stable field names and its schema version", func(t *testing.T) {
+		got, err := json.Marshal(file.File{
+			Package: "test",
+			Imports: file.Imports{{Path: "fmt"}},
+			Funcs: []file.Implementation{{
+				Func:      file.Method{Name: "Hello", Params: file.Args{{Name: "name", Type: "string"}}},
+				CodeLines: []string{`fmt.Println("hello", name)`},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"version":1,"package":"test","imports":[{"path":"fmt"}],` +
+			`"funcs":[{"func":{"name":"Hello","params":[{"name":"name","type":"string"}]},` +
+			`"codeLines":["fmt.Println(\"hello\", name)"]}]}`
+		if string(got) != want {
+			t.Errorf("File.MarshalJSON() failed, want: \n%s\ngot\n%s", want, got)
+		}
+	})
+
+	t.Run("should decode the file it encoded", func(t *testing.T) {
+		data, err := json.Marshal(jsonFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got file.File
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != jsonFile.String() {
+			t.Errorf("File.UnmarshalJSON() failed, want: \n%s\ngot\n%s", jsonFile.String(), got.String())
+		}
+	})
+}
+
+func TestFile_UnmarshalJSON(t *testing.T) {
+	type testCase struct {
+		it      string
+		data    string
+		wantErr error
+	}
+
+	tc := []testCase{
+		{
+			it:      "should reject files without schema version",
+			data:    `{"package":"test"}`,
+			wantErr: file.ErrUnsupportedSchema,
+		},
+		{
+			it:      "should reject files of another schema version",
+			data:    `{"version":2,"package":"test"}`,
+			wantErr: file.ErrUnsupportedSchema,
+		},
+		{
+			it:      "should reject variadic params before the last one",
+			data:    `{"version":1,"package":"test","funcs":[{"func":{"name":"f","params":[{"name":"a","type":"int","variadic":true},{"name":"b","type":"int"}]}}]}`,
+			wantErr: file.ErrMisplacedVariadic,
+		},
+		{
+			it:      "should reject variadic results of interface methods",
+			data:    `{"version":1,"package":"test","interfaces":[{"name":"I","methods":[{"name":"f","results":[{"type":"int","variadic":true}]}]}]}`,
+			wantErr: file.ErrMisplacedVariadic,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			var f file.File
+			if err := json.Unmarshal([]byte(c.data), &f); !errors.Is(err, c.wantErr) {
+				t.Errorf("File.UnmarshalJSON() failed, want: %v got %v", c.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		var f file.File
+		err := json.Unmarshal([]byte(`{"version":1,"package":"test","funcs":[{"func":{"name":"Hello"},"lines":["return"]}]}`), &f)
+		if err == nil {
+			t.Errorf("File.UnmarshalJSON() failed, want: unknown field \"lines\" got %v", err)
+		}
+	})
+}
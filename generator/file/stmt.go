@@ -0,0 +1,255 @@
+package file
+
+import "strings"
+
+// Expr is a Go expression along with the imports it references.
+type Expr struct {
+	code    string
+	imports Imports
+}
+
+// Code returns the expression written as code, referencing the imports.
+func Code(code string, imports ...Import) Expr {
+	return Expr{code: code, imports: imports}
+}
+
+// Qual returns the exported name of the imported package, as in errors.New.
+func Qual(imp Import, name string) Expr {
+	return Code(imp.PackageName()+"."+name, imp)
+}
+
+// Call returns the call of the expression with the arguments.
+func (e Expr) Call(args ...Expr) Expr {
+	call := Expr{code: e.code + "(" + exprList(args) + ")"}
+	call.imports = append(call.imports, e.imports...)
+	for _, arg := range args {
+		call.imports = append(call.imports, arg.imports...)
+	}
+	return call
+}
+
+// Lit returns the composite literal of the expression, a type, with the
+// elements.
+func (e Expr) Lit(elems ...Expr) Expr {
+	lit := Expr{code: e.code + "{" + exprList(elems) + "}"}
+	lit.imports = append(lit.imports, e.imports...)
+	lit.imports = append(lit.imports, exprImports(elems...)...)
+	return lit
+}
+
+func (e Expr) String() string {
+	return e.code
+}
+
+func exprList(exprs []Expr) string {
+	codes := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		codes = append(codes, e.code)
+	}
+	return strings.Join(codes, ", ")
+}
+
+func exprImports(exprs ...Expr) Imports {
+	var imports Imports
+	for _, e := range exprs {
+		imports = append(imports, e.imports...)
+	}
+	return imports
+}
+
+// Stmt is a statement of a function body, built by the functions of this
+// file.
+type Stmt interface {
+	// lines returns the lines of the statement, the ones of its nested
+	// blocks indented with a tab per level.
+	lines() []string
+	// imports returns the imports the statement references.
+	imports() Imports
+}
+
+// Block is a list of statements, rendered one after the other. Nested in
+// another block, its statements are spliced into it.
+type Block []Stmt
+
+// Lines returns the lines of the statements, as they go in CodeLines.
+func (b Block) Lines() []string {
+	return b.lines()
+}
+
+// Imports returns the imports the statements reference.
+func (b Block) Imports() Imports {
+	return b.imports()
+}
+
+func (b Block) lines() []string {
+	lines := make([]string, 0, len(b))
+	for _, s := range b {
+		lines = append(lines, s.lines()...)
+	}
+	return lines
+}
+
+func (b Block) imports() Imports {
+	var imports Imports
+	for _, s := range b {
+		imports = append(imports, s.imports()...)
+	}
+	return imports
+}
+
+// indented returns the lines of the block one level deeper.
+func (b Block) indented() []string {
+	lines := b.lines()
+	for i := range lines {
+		lines[i] = "\t" + lines[i]
+	}
+	return lines
+}
+
+type simpleStmt struct {
+	code   string
+	values []Expr
+}
+
+func (s simpleStmt) lines() []string  { return []string{s.code} }
+func (s simpleStmt) imports() Imports { return exprImports(s.values...) }
+
+// Line returns the statement written as a line of code, referencing the
+// imports, for the statements this file has no builder for.
+func Line(code string, imports ...Import) Stmt {
+	return simpleStmt{code: code, values: []Expr{Code(code, imports...)}}
+}
+
+// Return returns the return statement of the values.
+func Return(values ...Expr) Stmt {
+	if len(values) == 0 {
+		return simpleStmt{code: "return"}
+	}
+	return simpleStmt{code: "return " + exprList(values), values: values}
+}
+
+// Define returns the short variable declaration of lhs, e.g. "item, err",
+// with the values.
+func Define(lhs string, values ...Expr) Stmt {
+	return simpleStmt{code: lhs + " := " + exprList(values), values: values}
+}
+
+// Assign returns the assignment of the values to lhs.
+func Assign(lhs string, values ...Expr) Stmt {
+	return simpleStmt{code: lhs + " = " + exprList(values), values: values}
+}
+
+// Eval returns the statement evaluating the expression, usually a call.
+func Eval(e Expr) Stmt {
+	return simpleStmt{code: e.code, values: []Expr{e}}
+}
+
+type scopeStmt struct {
+	header string
+	values []Expr
+	body   Block
+}
+
+func (s scopeStmt) lines() []string {
+	lines := append([]string{s.header + "{"}, s.body.indented()...)
+	return append(lines, "}")
+}
+
+func (s scopeStmt) imports() Imports {
+	return append(exprImports(s.values...), s.body.imports()...)
+}
+
+// Scope returns the statements enclosed in a block of their own.
+func Scope(body ...Stmt) Stmt {
+	return scopeStmt{body: body}
+}
+
+// For returns the for loop of the clause, as in "i := 0; i < n; i++" or
+// "ok", looping forever when it is empty.
+func For(clause Expr, body ...Stmt) Stmt {
+	header := "for "
+	if clause.code != "" {
+		header += clause.code + " "
+	}
+	return scopeStmt{header: header, values: []Expr{clause}, body: body}
+}
+
+// Range returns the for loop ranging over the expression, assigning vars,
+// e.g. "_, item", unless it is empty.
+func Range(vars string, over Expr, body ...Stmt) Stmt {
+	header := "for range "
+	if vars != "" {
+		header = "for " + vars + " := range "
+	}
+	return scopeStmt{header: header + over.code + " ", values: []Expr{over}, body: body}
+}
+
+type branch struct {
+	cond Expr
+	body Block
+}
+
+// IfStmt is an if statement and its else branches.
+type IfStmt struct {
+	initStmt Stmt
+	branches []branch
+	elseBody Block
+}
+
+// If returns the if statement running body when cond holds.
+func If(cond Expr, body ...Stmt) IfStmt {
+	return IfStmt{branches: []branch{{cond: cond, body: body}}}
+}
+
+// Init returns the if statement running the simple statement init before
+// its condition, as in "if err := f(); err != nil".
+func (s IfStmt) Init(init Stmt) IfStmt {
+	s.initStmt = init
+	return s
+}
+
+// ElseIf returns the if statement with an else if branch added.
+func (s IfStmt) ElseIf(cond Expr, body ...Stmt) IfStmt {
+	s.branches = append(append([]branch(nil), s.branches...), branch{cond: cond, body: body})
+	return s
+}
+
+// Else returns the if statement running body when no branch ran.
+func (s IfStmt) Else(body ...Stmt) IfStmt {
+	s.elseBody = body
+	return s
+}
+
+func (s IfStmt) lines() []string {
+	header := "if "
+	if s.initStmt != nil {
+		header += strings.Join(s.initStmt.lines(), " ") + "; "
+	}
+
+	lines := make([]string, 0)
+	for i, b := range s.branches {
+		if i == 0 {
+			lines = append(lines, header+b.cond.code+" {")
+		} else {
+			lines = append(lines, "} else if "+b.cond.code+" {")
+		}
+		lines = append(lines, b.body.indented()...)
+	}
+	if len(s.elseBody) > 0 {
+		lines = append(lines, "} else {")
+		lines = append(lines, s.elseBody.indented()...)
+	}
+	return append(lines, "}")
+}
+
+func (s IfStmt) imports() Imports {
+	var imports Imports
+	if s.initStmt != nil {
+		imports = append(imports, s.initStmt.imports()...)
+	}
+	for _, b := range s.branches {
+		imports = append(imports, b.cond.imports...)
+		imports = append(imports, b.body.imports()...)
+	}
+	return append(imports, s.elseBody.imports()...)
+}
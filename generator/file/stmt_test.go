@@ -0,0 +1,167 @@
+package file_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+func TestBlock_Lines(t *testing.T) {
+	errorsImport := file.Import{Path: "errors"}
+
+	type testCase struct {
+		it   string
+		body file.Block
+		want []string
+	}
+
+	tc := []testCase{
+		{
+			it: "should render simple statements",
+			body: file.Block{
+				file.Define("item, err", file.Code("repo.Get").Call(file.Code("ctx"), file.Code("id"))),
+				file.Assign("item.Name", file.Code(`"xpto"`)),
+				file.Eval(file.Code("fmt.Println").Call(file.Code("item"))),
+				file.Define("ids", file.Code("[]string").Lit(file.Code(`"a"`), file.Code(`"b"`))),
+				file.Line("defer cancel()"),
+				file.Return(file.Code("item"), file.Code("nil")),
+				file.Return(),
+			},
+			want: []string{
+				"item, err := repo.Get(ctx, id)",
+				`item.Name = "xpto"`,
+				"fmt.Println(item)",
+				`ids := []string{"a", "b"}`,
+				"defer cancel()",
+				"return item, nil",
+				"return",
+			},
+		},
+		{
+			it: "should indent nested blocks",
+			body: file.Block{
+				file.Range("_, item", file.Code("items"),
+					file.If(file.Code("item.ID == id"),
+						file.For(file.Code("i := 0; i < 3; i++"),
+							file.Eval(file.Code("retry").Call(file.Code("i"))),
+						),
+						file.Return(file.Code("item")),
+					),
+				),
+				file.For(file.Code(""), file.Line("break")),
+				file.Scope(file.Line("x := 1")),
+			},
+			want: []string{
+				"for _, item := range items {",
+				"\tif item.ID == id {",
+				"\t\tfor i := 0; i < 3; i++ {",
+				"\t\t\tretry(i)",
+				"\t\t}",
+				"\t\treturn item",
+				"\t}",
+				"}",
+				"for {",
+				"\tbreak",
+				"}",
+				"{",
+				"\tx := 1",
+				"}",
+			},
+		},
+		{
+			it: "should render else branches and init statements",
+			body: file.Block{
+				file.If(file.Qual(errorsImport, "Is").Call(file.Code("err"), file.Code("ErrNotFound")),
+					file.Return(file.Code("404")),
+				).Init(file.Define("err", file.Code("f()"))).
+					ElseIf(file.Code("err != nil"), file.Return(file.Code("500"))).
+					Else(file.Return(file.Code("200"))),
+			},
+			want: []string{
+				"if err := f(); errors.Is(err, ErrNotFound) {",
+				"\treturn 404",
+				"} else if err != nil {",
+				"\treturn 500",
+				"} else {",
+				"\treturn 200",
+				"}",
+			},
+		},
+		{
+			it: "should splice nested blocks",
+			body: file.Block{
+				file.Block{file.Line("a()"), file.Line("b()")},
+				file.Line("c()"),
+			},
+			want: []string{"a()", "b()", "c()"},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			got := strings.Join(c.body.Lines(), "\n")
+			if want := strings.Join(c.want, "\n"); got != want {
+				t.Errorf("Block.Lines() failed, want: \n%s\ngot\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestImplementation_Body(t *testing.T) {
+	f := file.File{
+		Package: "test",
+		Imports: file.Imports{{Path: "context"}},
+		Funcs: []file.Implementation{{
+			Func: file.Method{
+				Name:    "find",
+				Params:  file.Args{{Name: "ctx", Type: "context.Context"}},
+				Results: file.Args{{Type: "error"}},
+			},
+			CodeLines: []string{"err := ctx.Err()"},
+			Body: file.Block{
+				file.If(file.Code("err != nil"),
+					file.Return(file.Qual(file.Import{Path: "fmt"}, "Errorf").Call(file.Code(`"find: %w"`), file.Code("err"))),
+				),
+				file.Return(file.Qual(file.Import{Path: "errors"}, "New").Call(file.Code(`"not found"`))),
+			},
+			UserRegion: true,
+		}},
+	}
+	want := "package test\n\n" +
+		"import (\n" +
+		"\t\"context\"\n" +
+		"\t\"errors\"\n" +
+		"\t\"fmt\"\n" +
+		")\n\n" +
+		"func find(ctx context.Context) error {\n" +
+		"\t// microcli:begin find\n" +
+		"\terr := ctx.Err()\n" +
+		"\tif err != nil {\n" +
+		"\t\treturn fmt.Errorf(\"find: %w\", err)\n" +
+		"\t}\n" +
+		"\treturn errors.New(\"not found\")\n" +
+		"\t// microcli:end\n" +
+		"}\n"
+
+	t.Run("should render the body after the code lines and import what it references", func(t *testing.T) {
+		if got := f.String(); got != want {
+			t.Errorf("File.String() failed, want: \n%s\ngot\n%s", want, got)
+		}
+	})
+
+	t.Run("should encode the body as code lines", func(t *testing.T) {
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got file.File
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want {
+			t.Errorf("File.MarshalJSON() failed, want: \n%s\ngot\n%s", want, got.String())
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidTag = errors.New("invalid struct tag")
+
+// Tag is a struct tag, rendered with its keys in order.
+type Tag []TagKey
+
+// TagKey is a key of a struct tag and its value, as in
+// json:"name,omitempty", Options holding what follows the first comma.
+type TagKey struct {
+	Key     string   `json:"key"`
+	Value   string   `json:"value"`
+	Options []string `json:"options,omitempty"`
+}
+
+// String returns the tag quoted in backticks, as it goes in Field.Tag, or
+// nothing when it has no keys.
+func (t Tag) String() string {
+	if len(t) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(t))
+	for _, k := range t {
+		parts = append(parts, k.String())
+	}
+	return "`" + strings.Join(parts, " ") + "`"
+}
+
+func (k TagKey) String() string {
+	value := strings.Join(append([]string{k.Value}, k.Options...), ",")
+	return k.Key + ":" + strconv.Quote(value)
+}
+
+// Get returns the key of the tag.
+func (t Tag) Get(key string) (TagKey, bool) {
+	for _, k := range t {
+		if k.Key == key {
+			return k, true
+		}
+	}
+	return TagKey{}, false
+}
+
+// Set returns the tag with key set to the value and options, in place of the
+// previous ones or after the other keys.
+func (t Tag) Set(key string, value string, options ...string) Tag {
+	set := TagKey{Key: key, Value: value, Options: options}
+	tag := append(Tag(nil), t...)
+	for i := range tag {
+		if tag[i].Key == key {
+			tag[i] = set
+			return tag
+		}
+	}
+	return append(tag, set)
+}
+
+// Validate reports the keys that cannot be written in a struct tag: the
+// empty ones, the ones holding spaces, colons or quotes, and the ones whose
+// value or options hold backticks, which would end the raw string the tag is
+// written in.
+func (t Tag) Validate() error {
+	for _, k := range t {
+		if k.Key == "" || strings.ContainsAny(k.Key, " :\"`") || strings.IndexFunc(k.Key, unicode.IsControl) >= 0 {
+			return fmt.Errorf("%w: key %q", ErrInvalidTag, k.Key)
+		}
+		for _, value := range append([]string{k.Value}, k.Options...) {
+			if strings.Contains(value, "`") {
+				return fmt.Errorf("%w: %s holds a backtick", ErrInvalidTag, k.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseTag parses a struct tag written by hand, with or without its
+// backticks, following the key:"value" convention of reflect.StructTag.
+// Backticks are rejected within the tag.
+func ParseTag(raw string) (Tag, error) {
+	s := strings.TrimSpace(raw)
+	if strings.HasPrefix(s, "`") && strings.HasSuffix(s, "`") && len(s) > 1 {
+		s = s[1 : len(s)-1]
+	}
+	if strings.Contains(s, "`") {
+		return nil, fmt.Errorf("%w: %s holds a backtick", ErrInvalidTag, raw)
+	}
+
+	tag := Tag{}
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return tag, nil
+		}
+
+		i := 0
+		for i < len(s) && s[i] > ' ' && s[i] != ':' && s[i] != '"' && s[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(s) || s[i] != ':' || s[i+1] != '"' {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTag, raw)
+		}
+		key := s[:i]
+		s = s[i+1:]
+
+		// the quoted value ends at the first quote that is not escaped
+		i = 1
+		for i < len(s) && s[i] != '"' {
+			if s[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(s) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTag, raw)
+		}
+		value, err := strconv.Unquote(s[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTag, raw)
+		}
+		s = s[i+1:]
+
+		parts := strings.Split(value, ",")
+		k := TagKey{Key: key, Value: parts[0]}
+		if len(parts) > 1 {
+			k.Options = parts[1:]
+		}
+		tag = append(tag, k)
+	}
+}
@@ -0,0 +1,145 @@
+package file_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+func TestTag_String(t *testing.T) {
+	type testCase struct {
+		it   string
+		tag  file.Tag
+		want string
+	}
+
+	tc := []testCase{
+		{
+			it:   "should return nothing for empty tags",
+			tag:  file.Tag{},
+			want: "",
+		},
+		{
+			it: "should return the keys in order with their options",
+			tag: file.Tag{
+				{Key: "json", Value: "name", Options: []string{"omitempty"}},
+				{Key: "db", Value: "name"},
+				{Key: "validate", Value: "required", Options: []string{"min=3"}},
+			},
+			want: "`" + `json:"name,omitempty" db:"name" validate:"required,min=3"` + "`",
+		},
+		{
+			it:   "should quote the values",
+			tag:  file.Tag{{Key: "doc", Value: `the "name"`}},
+			want: "`" + `doc:"the \"name\""` + "`",
+		},
+		{
+			it:   "should replace the keys set again in place",
+			tag:  file.Tag{}.Set("json", "name").Set("db", "name").Set("json", "-"),
+			want: "`" + `json:"-" db:"name"` + "`",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			if got := c.tag.String(); got != c.want {
+				t.Errorf("Tag.String() failed, want: %s got %s", c.want, got)
+			}
+		})
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	type testCase struct {
+		it      string
+		raw     string
+		want    string
+		wantErr error
+	}
+
+	tc := []testCase{
+		{
+			it:   "should parse tags within backticks",
+			raw:  "`" + `json:"name,omitempty"  db:"name"` + "`",
+			want: "`" + `json:"name,omitempty" db:"name"` + "`",
+		},
+		{
+			it:   "should parse tags without backticks and escaped quotes",
+			raw:  `doc:"the \"name\"" validate:"required,min=3"`,
+			want: "`" + `doc:"the \"name\"" validate:"required,min=3"` + "`",
+		},
+		{
+			it:   "should parse empty tags",
+			raw:  "``",
+			want: "",
+		},
+		{
+			it:      "should fail on keys without value",
+			raw:     `json`,
+			wantErr: file.ErrInvalidTag,
+		},
+		{
+			it:      "should fail on unquoted values",
+			raw:     `json:name`,
+			wantErr: file.ErrInvalidTag,
+		},
+		{
+			it:      "should fail on unterminated values",
+			raw:     `json:"name`,
+			wantErr: file.ErrInvalidTag,
+		},
+		{
+			it:      "should fail on backticks within the tag",
+			raw:     "`json:\"na`me\"`",
+			wantErr: file.ErrInvalidTag,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			tag, err := file.ParseTag(c.raw)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("ParseTag() failed, want: %v got %v", c.wantErr, err)
+			}
+			if err == nil && tag.String() != c.want {
+				t.Errorf("ParseTag() failed, want: %s got %s", c.want, tag.String())
+			}
+		})
+	}
+
+	t.Run("should split the options of the values", func(t *testing.T) {
+		tag, err := file.ParseTag(`json:"name,omitempty,string"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, ok := tag.Get("json")
+		if !ok || key.Value != "name" || len(key.Options) != 2 || key.Options[1] != "string" {
+			t.Errorf("ParseTag() failed, want: name [omitempty string] got %v", key)
+		}
+	})
+}
+
+func TestTag_Validate(t *testing.T) {
+	type testCase struct {
+		it      string
+		tag     file.Tag
+		wantErr error
+	}
+
+	tc := []testCase{
+		{it: "should accept valid tags", tag: file.Tag{}.Set("json", "name", "omitempty")},
+		{it: "should reject empty keys", tag: file.Tag{}.Set("", "name"), wantErr: file.ErrInvalidTag},
+		{it: "should reject keys with colons", tag: file.Tag{}.Set("db:x", "name"), wantErr: file.ErrInvalidTag},
+		{it: "should reject backticks in values", tag: file.Tag{}.Set("db", "na`me"), wantErr: file.ErrInvalidTag},
+		{it: "should reject backticks in options", tag: file.Tag{}.Set("json", "name", "omit`empty"), wantErr: file.ErrInvalidTag},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			if err := c.tag.Validate(); !errors.Is(err, c.wantErr) {
+				t.Errorf("Validate() failed, want: %v got %v", c.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/spf13/pflag"
+)
+
+var ErrDuplicateGenerator = errors.New("generator already registered")
+
+// Generator produces the files of an entity. Every registered generator is
+// exposed by the CLI as a generate subcommand taking the entity name.
+type Generator interface {
+	// Name is the name of the generate subcommand.
+	Name() string
+	// Flags declares the flags of the generator on its subcommand.
+	Flags(flags *pflag.FlagSet)
+	// Generate returns the files of the entity.
+	Generate(ctx context.Context, name entity.EntityName, config Config) ([]Output, error)
+}
+
+// Describer is implemented by the generators documenting their subcommand.
+type Describer interface {
+	Short() string
+	Aliases() []string
+}
+
+// RouteGenerator is implemented by the generators of HTTP routes, after
+// which the OpenAPI spec of the project is refreshed.
+type RouteGenerator interface {
+	GeneratesRoutes() bool
+}
+
+// Config holds the project the generators work on and the flags they
+// declared, as parsed.
+type Config struct {
+	Dir            string
+	Module         string
+	StructsDir     string
+	Implementation entity.ImplementationConfig
+	Tags           entity.TagNaming
+	Flags          *pflag.FlagSet
+}
+
+// Output is a file produced by a generator, at Path relative to the project
+// root. Go files are merged into the existing ones when regenerated, the
+// other ones are written as is, except for Go sources given as Content which
+// are formatted and keep their user regions.
+type Output struct {
+	Path    string
+	File    file.File
+	Content []byte
+}
+
+func GoFile(path string, f file.File) Output {
+	return Output{Path: path, File: f}
+}
+
+func RawFile(path string, content string) Output {
+	return Output{Path: path, Content: []byte(content)}
+}
+
+// IsGo reports whether the output is a Go file.
+func (o Output) IsGo() bool {
+	return o.Content == nil
+}
+
+var (
+	mu         sync.RWMutex
+	generators = make(map[string]Generator)
+)
+
+// Register adds the generator to the registry, failing when its name is
+// taken.
+func Register(g Generator) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := generators[g.Name()]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateGenerator, g.Name())
+	}
+	generators[g.Name()] = g
+	return nil
+}
+
+// MustRegister registers the generator, panicking when its name is taken,
+// as generators register from init functions.
+func MustRegister(g Generator) {
+	if err := Register(g); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the generator registered under name.
+func Lookup(name string) (Generator, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	g, ok := generators[name]
+	return g, ok
+}
+
+// Generators returns the registered generators sorted by name.
+func Generators() []Generator {
+	mu.RLock()
+	defer mu.RUnlock()
+	all := make([]Generator, 0, len(generators))
+	for _, g := range generators {
+		all = append(all, g)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all
+}
@@ -0,0 +1,74 @@
+package generator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator"
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/tests/utils"
+	"github.com/spf13/pflag"
+)
+
+type readmeGenerator struct {
+	name string
+}
+
+func (g readmeGenerator) Name() string { return g.name }
+
+func (g readmeGenerator) Flags(flags *pflag.FlagSet) {}
+
+func (g readmeGenerator) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	return []generator.Output{generator.RawFile(name.SnakeCase()+".md", "# "+name.PascalCase()+"\n")}, nil
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("it should list the registered generators by name", func(t *testing.T) {
+		for _, name := range []string{"zz-readme", "aa-readme"} {
+			if err := generator.Register(readmeGenerator{name: name}); err != nil {
+				t.Fatalf("Register failed: %v", err)
+			}
+		}
+
+		names := make([]string, 0)
+		for _, g := range generator.Generators() {
+			names = append(names, g.Name())
+		}
+		if len(names) != 2 || names[0] != "aa-readme" || names[1] != "zz-readme" {
+			utils.Error(t, []string{"aa-readme", "zz-readme"}, names)
+		}
+		if _, ok := generator.Lookup("zz-readme"); !ok {
+			utils.Error(t, true, ok)
+		}
+	})
+
+	t.Run("it should reject generators whose name is taken", func(t *testing.T) {
+		err := generator.Register(readmeGenerator{name: "aa-readme"})
+		if !errors.Is(err, generator.ErrDuplicateGenerator) {
+			utils.Error(t, generator.ErrDuplicateGenerator, err)
+		}
+	})
+}
+
+func TestOutput_IsGo(t *testing.T) {
+	type testCase struct {
+		it     string
+		output generator.Output
+		want   bool
+	}
+
+	tc := []testCase{
+		{it: "should hold Go files", output: generator.GoFile("xpto.go", file.File{Package: "xpto"}), want: true},
+		{it: "should hold other files", output: generator.RawFile("xpto.md", ""), want: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			if got := c.output.IsGo(); got != c.want {
+				utils.Error(t, c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	PATH        = ".microcli/manifest.json"
+	HASH_PREFIX = "sha256:"
+
+	STATUS_UNCHANGED = "unchanged"
+	STATUS_MODIFIED  = "modified"
+	STATUS_MISSING   = "missing"
+)
+
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// Manifest records the files written by microcli, along with the inputs
+// they were generated from.
+type Manifest struct {
+	Files []Entry `json:"files"`
+}
+
+type Entry struct {
+	Path string `json:"path"`
+	// Hash is the hash of the generated content, which differs from the
+	// content written when user regions or declarations were kept.
+	Hash   string `json:"hash"`
+	Inputs Inputs `json:"inputs"`
+}
+
+// Inputs replay the generation of a file: Generator is the command path,
+// such as "generate repository", Args its arguments, the entity among them,
+// and Options its flags.
+type Inputs struct {
+	Generator   string   `json:"generator"`
+	Args        []string `json:"args,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	ToolVersion string   `json:"toolVersion"`
+}
+
+// Key identifies the generations sharing the same inputs, whatever the
+// version of the tool.
+func (i Inputs) Key() string {
+	return strings.Join(append(append([]string{i.Generator}, i.Args...), i.Options...), "\x00")
+}
+
+// Load reads the manifest of the project in dir, an empty one when the
+// project has none.
+func Load(dir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, PATH))
+	if errors.Is(err, os.ErrNotExist) {
+		return Manifest{Files: make([]Entry, 0)}, nil
+	}
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %s: %v", ErrInvalidManifest, PATH, err)
+	}
+	if m.Files == nil {
+		m.Files = make([]Entry, 0)
+	}
+	return m, nil
+}
+
+func (m Manifest) Save(dir string) error {
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	fullPath := filepath.Join(dir, PATH)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, append(data, '\n'), 0o644)
+}
+
+// Record adds the entry, replacing the one of the same path.
+func (m *Manifest) Record(entry Entry) {
+	entry.Path = filepath.ToSlash(filepath.Clean(entry.Path))
+	for i := range m.Files {
+		if m.Files[i].Path == entry.Path {
+			m.Files[i] = entry
+			return
+		}
+	}
+	m.Files = append(m.Files, entry)
+}
+
+func (m Manifest) Find(path string) (Entry, bool) {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for _, entry := range m.Files {
+		if entry.Path == path {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+func (m *Manifest) Remove(path string) {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for i := range m.Files {
+		if m.Files[i].Path == path {
+			m.Files = append(m.Files[:i], m.Files[i+1:]...)
+			return
+		}
+	}
+}
+
+// Status compares the file of the entry in dir to the generated content.
+func (e Entry) Status(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(e.Path)))
+	if errors.Is(err, os.ErrNotExist) {
+		return STATUS_MISSING, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if Hash(data) != e.Hash {
+		return STATUS_MODIFIED, nil
+	}
+	return STATUS_UNCHANGED, nil
+}
+
+// Generations returns the distinct inputs of the entries, in the order they
+// first appear.
+func (m Manifest) Generations() []Inputs {
+	seen := make(map[string]bool, len(m.Files))
+	generations := make([]Inputs, 0)
+	for _, entry := range m.Files {
+		key := entry.Inputs.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		generations = append(generations, entry.Inputs)
+	}
+	return generations
+}
+
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return HASH_PREFIX + hex.EncodeToString(sum[:])
+}
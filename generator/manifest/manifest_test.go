@@ -0,0 +1,120 @@
+package manifest_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("it should return an empty manifest when the project has none", func(t *testing.T) {
+		m, err := manifest.Load(t.TempDir())
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(m.Files) != 0 {
+			utils.Error(t, 0, len(m.Files))
+		}
+	})
+
+	t.Run("it should read the saved entries sorted by path", func(t *testing.T) {
+		dir := t.TempDir()
+		repository := manifest.Entry{
+			Path:   "src/repositories/xpto/xpto_repository.go",
+			Hash:   manifest.Hash([]byte("repository")),
+			Inputs: manifest.Inputs{Generator: "generate repository", Args: []string{"Xpto"}, ToolVersion: "v1.0.0"},
+		}
+		handler := manifest.Entry{
+			Path:   "src/handlers/xpto/xpto_handler.go",
+			Hash:   manifest.Hash([]byte("handler")),
+			Inputs: manifest.Inputs{Generator: "generate handler", Args: []string{"Xpto"}, ToolVersion: "v1.0.0"},
+		}
+
+		var m manifest.Manifest
+		m.Record(repository)
+		m.Record(handler)
+		repository.Hash = manifest.Hash([]byte("regenerated"))
+		m.Record(repository)
+		if err := m.Save(dir); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		loaded, err := manifest.Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		want := []manifest.Entry{handler, repository}
+		if !reflect.DeepEqual(loaded.Files, want) {
+			utils.Error(t, want, loaded.Files)
+		}
+	})
+
+	t.Run("it should fail on invalid manifests", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, ".microcli"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, manifest.PATH), []byte("{"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := manifest.Load(dir); !errors.Is(err, manifest.ErrInvalidManifest) {
+			utils.Error(t, manifest.ErrInvalidManifest, err)
+		}
+	})
+}
+
+func TestEntry_Status(t *testing.T) {
+	type testCase struct {
+		it      string
+		content string
+		want    string
+	}
+
+	tc := []testCase{
+		{it: "should report files matching their generated content", content: "generated", want: manifest.STATUS_UNCHANGED},
+		{it: "should report files edited since generated", content: "edited", want: manifest.STATUS_MODIFIED},
+		{it: "should report removed files", want: manifest.STATUS_MISSING},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			dir := t.TempDir()
+			entry := manifest.Entry{Path: "xpto.go", Hash: manifest.Hash([]byte("generated"))}
+			if c.content != "" {
+				if err := os.WriteFile(filepath.Join(dir, entry.Path), []byte(c.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			status, err := entry.Status(dir)
+			if err != nil {
+				t.Fatalf("Status failed: %v", err)
+			}
+			if status != c.want {
+				utils.Error(t, c.want, status)
+			}
+		})
+	}
+}
+
+func TestManifest_Generations(t *testing.T) {
+	t.Run("it should return each distinct generation once", func(t *testing.T) {
+		handler := manifest.Inputs{Generator: "generate handler", Args: []string{"Xpto"}, Options: []string{"--framework=chi"}}
+		service := manifest.Inputs{Generator: "generate service", Args: []string{"Xpto"}}
+		m := manifest.Manifest{Files: []manifest.Entry{
+			{Path: "src/handlers/xpto/xpto_handler.go", Inputs: handler},
+			{Path: "src/handlers/xpto/xpto_handler_test.go", Inputs: handler},
+			{Path: "src/services/xpto/xpto_service.go", Inputs: service},
+		}}
+
+		want := []manifest.Inputs{handler, service}
+		if got := m.Generations(); !reflect.DeepEqual(got, want) {
+			utils.Error(t, want, got)
+		}
+	})
+}
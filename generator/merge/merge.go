@@ -0,0 +1,263 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+var ErrInvalidSource = errors.New("invalid existing source")
+
+type insertion struct {
+	offset int
+	text   string
+}
+
+// existing indexes the declarations of the parsed file.
+type existing struct {
+	fset       *token.FileSet
+	file       *ast.File
+	imports    map[string]bool
+	values     map[string]bool
+	types      map[string]ast.Expr
+	funcs      map[string]bool
+	methods    map[string]bool
+	importDecl *ast.GenDecl
+}
+
+// File adds to src the declarations of generated that it lacks: imports,
+// vars, functions, interfaces, interface methods, structs and methods. The
+// declarations already in src are left untouched, the missing ones are
+// inserted as generated. It returns the merged source and the names of the
+// added declarations, src being returned as is when nothing is missing.
+func File(generated file.File, src []byte) ([]byte, []string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSource, err)
+	}
+	if f.Name.Name != generated.Package {
+		return nil, nil, fmt.Errorf("%w: package %s, generated package is %s", ErrInvalidSource, f.Name.Name, generated.Package)
+	}
+	ex := index(fset, f)
+
+	insertions := make([]insertion, 0)
+	added := make([]string, 0)
+	end := len(src)
+	appendDecl := func(name string, text string) {
+		insertions = append(insertions, insertion{offset: end, text: text})
+		added = append(added, name)
+	}
+
+	for _, v := range generated.Vars {
+		if !ex.values[v.Name] {
+			appendDecl(v.Name, v.String())
+		}
+	}
+	for _, t := range generated.Types {
+		if _, ok := ex.types[t.Name]; !ok {
+			appendDecl(t.Name, t.String())
+		}
+	}
+	for _, fn := range generated.Funcs {
+		if !ex.declares(fn) {
+			appendDecl(fn.RegionKey(), fn.String())
+		}
+	}
+	for _, i := range generated.Interfaces {
+		typ, ok := ex.types[i.Name]
+		if !ok {
+			appendDecl(i.Name, i.String())
+			continue
+		}
+		iface, ok := typ.(*ast.InterfaceType)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is not an interface", ErrInvalidSource, i.Name)
+		}
+		methods := interfaceMethods(iface)
+		offset, prefix := lineStart(src, fset.Position(iface.Methods.Closing))
+		for _, embedded := range i.Embeds {
+			if !methods[embedded.Name] {
+				insertions = append(insertions, insertion{offset: offset, text: prefix + "\t" + embedded.Name + "\n"})
+				added = append(added, i.Name+"."+embedded.Name)
+			}
+		}
+		for _, m := range i.Methods {
+			if !methods[m.Name] {
+				insertions = append(insertions, insertion{offset: offset, text: prefix + "\t" + m.String() + "\n"})
+				added = append(added, i.Name+"."+m.Name)
+			}
+		}
+	}
+	for _, s := range generated.Structs {
+		if _, ok := ex.types[s.Name]; !ok {
+			appendDecl(s.Name, s.String())
+			continue
+		}
+		for _, impl := range s.Implementations {
+			if !ex.declares(impl) {
+				appendDecl(impl.RegionKey(), impl.String())
+			}
+		}
+	}
+	if len(added) == 0 {
+		return src, added, nil
+	}
+
+	code := make([]string, 0, len(insertions))
+	for _, ins := range insertions {
+		code = append(code, ins.text)
+	}
+	if text, offset := ex.missingImports(generated.AllImports(), strings.Join(code, ""), len(src)); text != "" {
+		insertions = append(insertions, insertion{offset: offset, text: text})
+	}
+	merged, err := format.Source(apply(src, insertions))
+	if err != nil {
+		return nil, nil, fmt.Errorf("merged invalid code: %w", err)
+	}
+	return merged, added, nil
+}
+
+func index(fset *token.FileSet, f *ast.File) existing {
+	ex := existing{
+		fset:    fset,
+		file:    f,
+		imports: make(map[string]bool),
+		values:  make(map[string]bool),
+		types:   make(map[string]ast.Expr),
+		funcs:   make(map[string]bool),
+		methods: make(map[string]bool),
+	}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		ex.imports[path] = true
+	}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				ex.importDecl = decl
+			}
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						ex.values[name.Name] = true
+					}
+				case *ast.TypeSpec:
+					ex.types[spec.Name.Name] = spec.Type
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				ex.funcs[decl.Name.Name] = true
+				continue
+			}
+			ex.methods[receiverName(decl.Recv.List[0].Type)+"."+decl.Name.Name] = true
+		}
+	}
+	return ex
+}
+
+// declares reports whether the function or method is declared in the file.
+func (ex existing) declares(impl file.Implementation) bool {
+	if impl.StructName == "" {
+		return ex.funcs[impl.Func.Name]
+	}
+	return ex.methods[impl.RegionKey()]
+}
+
+// missingImports renders the imports that the inserted code uses and the file
+// lacks, within the parenthesized import declaration when there is one.
+func (ex existing) missingImports(imports file.Imports, code string, end int) (string, int) {
+	missing := make(file.Imports, 0, len(imports))
+	seen := make(map[string]bool, len(imports))
+	for _, imp := range imports {
+		if !ex.imports[imp.Path] && !seen[imp.Path] && strings.Contains(code, imp.PackageName()+".") {
+			seen[imp.Path] = true
+			missing = append(missing, imp)
+		}
+	}
+	if len(missing) == 0 {
+		return "", end
+	}
+	sort.Sort(missing)
+
+	decl := ex.importDecl
+	if decl != nil && decl.Lparen.IsValid() {
+		var sb strings.Builder
+		for _, imp := range missing {
+			sb.WriteString("\t" + imp.String())
+		}
+		return sb.String(), ex.fset.Position(decl.Rparen).Offset
+	}
+	if decl != nil {
+		return "\n" + missing.String(), ex.fset.Position(decl.End()).Offset
+	}
+	return "\n" + missing.String(), ex.fset.Position(ex.file.Name.End()).Offset
+}
+
+// lineStart returns the offset of the line holding pos when only blanks
+// precede pos in it, or the offset of pos along with a line break otherwise.
+func lineStart(src []byte, pos token.Position) (int, string) {
+	start := pos.Offset - (pos.Column - 1)
+	if strings.TrimSpace(string(src[start:pos.Offset])) == "" {
+		return start, ""
+	}
+	return pos.Offset, "\n"
+}
+
+// interfaceMethods returns the names of the methods of the interface and of
+// the interfaces it embeds, as written.
+func interfaceMethods(iface *ast.InterfaceType) map[string]bool {
+	methods := make(map[string]bool)
+	for _, m := range iface.Methods.List {
+		if len(m.Names) == 0 {
+			methods[types.ExprString(m.Type)] = true
+		}
+		for _, name := range m.Names {
+			methods[name.Name] = true
+		}
+	}
+	return methods
+}
+
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// apply inserts the texts into src, the insertions at the same offset
+// keeping their order.
+func apply(src []byte, insertions []insertion) []byte {
+	sort.SliceStable(insertions, func(i, j int) bool {
+		return insertions[i].offset < insertions[j].offset
+	})
+	var sb strings.Builder
+	last := 0
+	for _, ins := range insertions {
+		sb.Write(src[last:ins.offset])
+		sb.WriteString(ins.text)
+		last = ins.offset
+	}
+	sb.Write(src[last:])
+	return []byte(sb.String())
+}
@@ -0,0 +1,174 @@
+package merge_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/merge"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func TestFile(t *testing.T) {
+	type testCase struct {
+		it        string
+		generated file.File
+		src       string
+		want      string
+		wantAdded []string
+		wantErr   error
+	}
+
+	get := file.Method{
+		Name:    "Get",
+		Params:  file.Args{{Name: "ctx", Type: "context.Context"}},
+		Results: file.Args{{Type: "error"}},
+	}
+	deleteMethod := file.Method{
+		Name:    "Delete",
+		Params:  file.Args{{Name: "ctx", Type: "context.Context"}, {Name: "id", Type: "uuid.UUID"}},
+		Results: file.Args{{Type: "error"}},
+	}
+	generated := file.File{
+		Package: "xpto",
+		Imports: file.Imports{{Path: "context"}, {Path: "github.com/google/uuid"}},
+		Interfaces: []file.Interface{
+			{Name: "Repository", Methods: []file.Method{get, deleteMethod}},
+		},
+		Structs: []file.Struct{
+			{
+				Name: "repository",
+				Implementations: []file.Implementation{
+					{StructAlias: "r", StructName: "*repository", Func: get, CodeLines: []string{"return nil"}},
+					{StructAlias: "r", StructName: "*repository", Func: deleteMethod, CodeLines: []string{"return nil"}},
+				},
+			},
+		},
+	}
+
+	tc := []testCase{
+		{
+			it:        "should add the missing methods and imports leaving the rest untouched",
+			generated: generated,
+			src: "package xpto\n\n" +
+				"import (\n" +
+				"\t\"context\"\n" +
+				")\n\n" +
+				"// Repository is written by hand\n" +
+				"type Repository interface {\n" +
+				"\tGet(ctx context.Context) error // keeps its comment\n" +
+				"}\n\n" +
+				"type repository struct{}\n\n" +
+				"func (r *repository) Get(ctx context.Context) error {\n" +
+				"\treturn context.Canceled\n" +
+				"}\n",
+			want: "package xpto\n\n" +
+				"import (\n" +
+				"\t\"context\"\n" +
+				"\t\"github.com/google/uuid\"\n" +
+				")\n\n" +
+				"// Repository is written by hand\n" +
+				"type Repository interface {\n" +
+				"\tGet(ctx context.Context) error // keeps its comment\n" +
+				"\tDelete(ctx context.Context, id uuid.UUID) error\n" +
+				"}\n\n" +
+				"type repository struct{}\n\n" +
+				"func (r *repository) Get(ctx context.Context) error {\n" +
+				"\treturn context.Canceled\n" +
+				"}\n\n" +
+				"func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {\n" +
+				"\treturn nil\n" +
+				"}\n",
+			wantAdded: []string{"Repository.Delete", "repository.Delete"},
+		},
+		{
+			it: "should add missing declarations and their imports to files without imports",
+			generated: file.File{
+				Package: "xpto",
+				Imports: file.Imports{{Path: "errors"}, {Path: "context"}},
+				Vars:    []file.Var{{Name: "ErrNotFound", Value: `errors.New("not found")`}},
+			},
+			src: "package xpto\n",
+			want: "package xpto\n\n" +
+				"import \"errors\"\n\n" +
+				"var ErrNotFound = errors.New(\"not found\")\n",
+			wantAdded: []string{"ErrNotFound"},
+		},
+		{
+			it: "should add the missing named types",
+			generated: file.File{
+				Package: "xpto",
+				Types: []file.Type{
+					{Name: "Option", Type: "func(*xpto)"},
+					{Name: "Status", Type: "int"},
+				},
+			},
+			src: "package xpto\n\ntype Status string\n",
+			want: "package xpto\n\ntype Status string\n\n" +
+				"type Option func(*xpto)\n",
+			wantAdded: []string{"Option"},
+		},
+		{
+			it: "should add the interfaces an existing interface lacks the embedding of",
+			generated: file.File{
+				Package: "xpto",
+				Imports: file.Imports{{Path: "io"}, {Path: "fmt"}},
+				Interfaces: []file.Interface{{
+					Name:   "Repository",
+					Embeds: []file.Interface{{Name: "io.Closer"}, {Name: "fmt.Stringer"}},
+				}},
+			},
+			src: "package xpto\n\n" +
+				"import \"fmt\"\n\n" +
+				"type Repository interface {\n" +
+				"\tfmt.Stringer\n" +
+				"}\n",
+			want: "package xpto\n\n" +
+				"import \"fmt\"\n\n" +
+				"import \"io\"\n\n" +
+				"type Repository interface {\n" +
+				"\tfmt.Stringer\n" +
+				"\tio.Closer\n" +
+				"}\n",
+			wantAdded: []string{"Repository.io.Closer"},
+		},
+		{
+			it:        "should return the file as is when nothing is missing",
+			generated: file.File{Package: "xpto", Vars: []file.Var{{Name: "x", Value: "1"}}},
+			src:       "package xpto\n\nvar x = 2 // hand written\n",
+			want:      "package xpto\n\nvar x = 2 // hand written\n",
+			wantAdded: []string{},
+		},
+		{
+			it:        "should fail when the packages differ",
+			generated: generated,
+			src:       "package other\n",
+			wantErr:   merge.ErrInvalidSource,
+		},
+		{
+			it:        "should fail when a generated interface is declared as another type",
+			generated: generated,
+			src:       "package xpto\n\ntype Repository struct{}\n",
+			wantErr:   merge.ErrInvalidSource,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			merged, added, err := merge.File(c.generated, []byte(c.src))
+			if !errors.Is(err, c.wantErr) {
+				utils.Error(t, c.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if string(merged) != c.want {
+				t.Errorf("merge.File() failed, want: \n%s\ngot\n%s", c.want, merged)
+			}
+			if !reflect.DeepEqual(added, c.wantAdded) {
+				utils.Error(t, c.wantAdded, added)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	VERSION      = "3.0.3"
+	JSON_CONTENT = "application/json"
+	REF_PREFIX   = "#/components/schemas/"
+)
+
+type Document struct {
+	Title      string
+	Version    string
+	Paths      []Path
+	Components []Component
+}
+
+func (d Document) String() string {
+	w := &writer{}
+	w.line(0, "openapi: "+VERSION)
+	w.line(0, "info:")
+	w.line(1, "title: "+scalar(d.Title))
+	w.line(1, "version: "+strconv.Quote(d.Version))
+	if len(d.Paths) == 0 {
+		w.line(0, "paths: {}")
+	} else {
+		w.line(0, "paths:")
+	}
+	for i := range d.Paths {
+		d.Paths[i].write(w, 1)
+	}
+	if len(d.Components) > 0 {
+		w.line(0, "components:")
+		w.line(1, "schemas:")
+	}
+	for i := range d.Components {
+		w.line(2, scalar(d.Components[i].Name)+":")
+		d.Components[i].Schema.write(w, 3)
+	}
+	return w.String()
+}
+
+type Path struct {
+	Pattern    string
+	Operations []Operation
+}
+
+func (p Path) write(w *writer, indent int) {
+	w.line(indent, scalar(p.Pattern)+":")
+	for i := range p.Operations {
+		p.Operations[i].write(w, indent+1)
+	}
+}
+
+type Operation struct {
+	Method      string
+	OperationID string
+	Summary     string
+	Tags        []string
+	Parameters  []Parameter
+	RequestBody *Schema
+	Responses   []Response
+}
+
+func (o Operation) write(w *writer, indent int) {
+	w.line(indent, strings.ToLower(o.Method)+":")
+	if o.OperationID != "" {
+		w.line(indent+1, "operationId: "+scalar(o.OperationID))
+	}
+	if o.Summary != "" {
+		w.line(indent+1, "summary: "+scalar(o.Summary))
+	}
+	if len(o.Tags) > 0 {
+		w.line(indent+1, "tags:")
+	}
+	for _, tag := range o.Tags {
+		w.line(indent+2, "- "+scalar(tag))
+	}
+	if len(o.Parameters) > 0 {
+		w.line(indent+1, "parameters:")
+	}
+	for i := range o.Parameters {
+		o.Parameters[i].write(w, indent+2)
+	}
+	if o.RequestBody != nil {
+		w.line(indent+1, "requestBody:")
+		w.line(indent+2, "required: true")
+		writeContent(w, indent+2, *o.RequestBody)
+	}
+	w.line(indent+1, "responses:")
+	for i := range o.Responses {
+		o.Responses[i].write(w, indent+2)
+	}
+}
+
+type Parameter struct {
+	Name     string
+	In       string
+	Required bool
+	Schema   Schema
+}
+
+func (p Parameter) write(w *writer, indent int) {
+	w.line(indent, "- name: "+scalar(p.Name))
+	w.line(indent+1, "in: "+scalar(p.In))
+	if p.Required {
+		w.line(indent+1, "required: true")
+	}
+	w.line(indent+1, "schema:")
+	p.Schema.write(w, indent+2)
+}
+
+type Response struct {
+	Status      int
+	Description string
+	Schema      *Schema
+}
+
+func (r Response) write(w *writer, indent int) {
+	w.line(indent, strconv.Quote(strconv.Itoa(r.Status))+":")
+	w.line(indent+1, "description: "+scalar(r.Description))
+	if r.Schema != nil {
+		writeContent(w, indent+1, *r.Schema)
+	}
+}
+
+func writeContent(w *writer, indent int, schema Schema) {
+	w.line(indent, "content:")
+	w.line(indent+1, JSON_CONTENT+":")
+	w.line(indent+2, "schema:")
+	schema.write(w, indent+3)
+}
+
+type Component struct {
+	Name   string
+	Schema Schema
+}
+
+type Schema struct {
+	Ref                  string
+	Type                 string
+	Format               string
+	Nullable             bool
+	Items                *Schema
+	AdditionalProperties *Schema
+	Properties           []Property
+	Required             []string
+}
+
+type Property struct {
+	Name   string
+	Schema Schema
+}
+
+func Ref(component string) Schema {
+	return Schema{Ref: component}
+}
+
+// Refs returns the components referenced by the schema, including the ones
+// referenced by its properties and items.
+func (s Schema) Refs() []string {
+	refs := make([]string, 0)
+	if s.Ref != "" {
+		refs = append(refs, s.Ref)
+	}
+	if s.Items != nil {
+		refs = append(refs, s.Items.Refs()...)
+	}
+	if s.AdditionalProperties != nil {
+		refs = append(refs, s.AdditionalProperties.Refs()...)
+	}
+	for _, property := range s.Properties {
+		refs = append(refs, property.Schema.Refs()...)
+	}
+	return refs
+}
+
+func (s Schema) write(w *writer, indent int) {
+	if s.Ref != "" {
+		w.line(indent, "$ref: "+strconv.Quote(REF_PREFIX+s.Ref))
+		return
+	}
+	if s.Type == "" && len(s.Properties) == 0 {
+		w.line(indent, "{}")
+		return
+	}
+	if s.Type != "" {
+		w.line(indent, "type: "+scalar(s.Type))
+	}
+	if s.Format != "" {
+		w.line(indent, "format: "+scalar(s.Format))
+	}
+	if s.Nullable {
+		w.line(indent, "nullable: true")
+	}
+	if s.Items != nil {
+		w.line(indent, "items:")
+		s.Items.write(w, indent+1)
+	}
+	if s.AdditionalProperties != nil {
+		w.line(indent, "additionalProperties:")
+		s.AdditionalProperties.write(w, indent+1)
+	}
+	if len(s.Required) > 0 {
+		w.line(indent, "required:")
+	}
+	for _, name := range s.Required {
+		w.line(indent+1, "- "+scalar(name))
+	}
+	if len(s.Properties) > 0 {
+		w.line(indent, "properties:")
+	}
+	for _, property := range s.Properties {
+		w.line(indent+1, scalar(property.Name)+":")
+		property.Schema.write(w, indent+2)
+	}
+}
+
+var plainScalar = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_ ./-]*[A-Za-z0-9_./-]$|^[A-Za-z_]$`)
+
+var reservedScalars = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true,
+	"on": true, "off": true, "null": true, "y": true, "n": true,
+}
+
+// scalar returns the value as a plain YAML scalar when that is unambiguous and
+// double quoted otherwise.
+func scalar(value string) string {
+	if plainScalar.MatchString(value) && !reservedScalars[strings.ToLower(value)] {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+type writer struct {
+	sb strings.Builder
+}
+
+func (w *writer) line(indent int, content string) {
+	w.sb.WriteString(strings.Repeat("  ", indent))
+	w.sb.WriteString(content)
+	w.sb.WriteString("\n")
+}
+
+func (w *writer) String() string {
+	return w.sb.String()
+}
@@ -0,0 +1,166 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/openapi"
+)
+
+func TestDocument_String(t *testing.T) {
+	type testCase struct {
+		it   string
+		doc  openapi.Document
+		want string
+	}
+
+	errorSchema := openapi.Ref("Error")
+	tc := []testCase{
+		{
+			it:  "should return a document without paths",
+			doc: openapi.Document{Title: "svc", Version: "1.0.0"},
+			want: "openapi: 3.0.3\n" +
+				"info:\n" +
+				"  title: svc\n" +
+				"  version: \"1.0.0\"\n" +
+				"paths: {}\n",
+		},
+		{
+			it: "should return a document with operations",
+			doc: openapi.Document{
+				Title:   "svc",
+				Version: "1.0.0",
+				Paths: []openapi.Path{
+					{
+						Pattern: "/xptos/{id}",
+						Operations: []openapi.Operation{
+							{
+								Method:      "PUT",
+								OperationID: "updateXpto",
+								Summary:     "Update xpto by id",
+								Tags:        []string{"Xpto"},
+								Parameters: []openapi.Parameter{
+									{Name: "id", In: "path", Required: true, Schema: openapi.Schema{Type: "string", Format: "uuid"}},
+								},
+								RequestBody: &openapi.Schema{Ref: "Xpto"},
+								Responses: []openapi.Response{
+									{Status: 204, Description: "Xpto updated"},
+									{Status: 400, Description: "Invalid: xpto", Schema: &errorSchema},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: "openapi: 3.0.3\n" +
+				"info:\n" +
+				"  title: svc\n" +
+				"  version: \"1.0.0\"\n" +
+				"paths:\n" +
+				"  \"/xptos/{id}\":\n" +
+				"    put:\n" +
+				"      operationId: updateXpto\n" +
+				"      summary: Update xpto by id\n" +
+				"      tags:\n" +
+				"        - Xpto\n" +
+				"      parameters:\n" +
+				"        - name: id\n" +
+				"          in: path\n" +
+				"          required: true\n" +
+				"          schema:\n" +
+				"            type: string\n" +
+				"            format: uuid\n" +
+				"      requestBody:\n" +
+				"        required: true\n" +
+				"        content:\n" +
+				"          application/json:\n" +
+				"            schema:\n" +
+				"              $ref: \"#/components/schemas/Xpto\"\n" +
+				"      responses:\n" +
+				"        \"204\":\n" +
+				"          description: Xpto updated\n" +
+				"        \"400\":\n" +
+				"          description: \"Invalid: xpto\"\n" +
+				"          content:\n" +
+				"            application/json:\n" +
+				"              schema:\n" +
+				"                $ref: \"#/components/schemas/Error\"\n",
+		},
+		{
+			it: "should return a document with components",
+			doc: openapi.Document{
+				Title:   "svc",
+				Version: "1.0.0",
+				Components: []openapi.Component{
+					{
+						Name: "Xpto",
+						Schema: openapi.Schema{
+							Type:     "object",
+							Required: []string{"tags"},
+							Properties: []openapi.Property{
+								{Name: "tags", Schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}},
+								{Name: "nickname", Schema: openapi.Schema{Type: "string", Nullable: true}},
+								{Name: "extra", Schema: openapi.Schema{}},
+							},
+						},
+					},
+				},
+			},
+			want: "openapi: 3.0.3\n" +
+				"info:\n" +
+				"  title: svc\n" +
+				"  version: \"1.0.0\"\n" +
+				"paths: {}\n" +
+				"components:\n" +
+				"  schemas:\n" +
+				"    Xpto:\n" +
+				"      type: object\n" +
+				"      required:\n" +
+				"        - tags\n" +
+				"      properties:\n" +
+				"        tags:\n" +
+				"          type: array\n" +
+				"          items:\n" +
+				"            type: string\n" +
+				"        nickname:\n" +
+				"          type: string\n" +
+				"          nullable: true\n" +
+				"        extra:\n" +
+				"          {}\n",
+		},
+		{
+			it:  "should quote ambiguous scalars",
+			doc: openapi.Document{Title: "yes", Version: "2"},
+			want: "openapi: 3.0.3\n" +
+				"info:\n" +
+				"  title: \"yes\"\n" +
+				"  version: \"2\"\n" +
+				"paths: {}\n",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			actual := c.doc.String()
+			if c.want != actual {
+				t.Errorf("Document.String() failed, want: \n%s\ngot\n%s", c.want, actual)
+			}
+		})
+	}
+}
+
+func TestSchema_Refs(t *testing.T) {
+	schema := openapi.Schema{
+		Type: "object",
+		Properties: []openapi.Property{
+			{Name: "owner", Schema: openapi.Ref("Owner")},
+			{Name: "addresses", Schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Ref: "Address"}}},
+			{Name: "labels", Schema: openapi.Schema{Type: "object", AdditionalProperties: &openapi.Schema{Ref: "Label"}}},
+		},
+	}
+
+	want := []string{"Owner", "Address", "Label"}
+	if actual := schema.Refs(); !reflect.DeepEqual(want, actual) {
+		t.Errorf("Schema.Refs() failed, want: %v, got %v", want, actual)
+	}
+}
@@ -0,0 +1,271 @@
+// Package plugin runs the external generators found on PATH, named after the
+// generate subcommand they implement, e.g. microcli-gen-foo for generate foo.
+//
+// A plugin reads a JSON Request on its standard input and writes on its
+// standard output the JSON list of the files it generated, as File values.
+// What it writes on its standard error is shown to the user, and it fails by
+// exiting with a non-zero status.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator"
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/file"
+	"github.com/eduardoths/micro-cli/generator/manifest"
+	"github.com/spf13/pflag"
+)
+
+const (
+	PREFIX = "microcli-gen-"
+
+	// PROTOCOL_VERSION is the version of the Request sent to the plugins,
+	// increased on breaking changes only.
+	PROTOCOL_VERSION = 1
+)
+
+var (
+	ErrNotFound      = errors.New("generator not found")
+	ErrInvalidOutput = errors.New("invalid plugin output")
+	ErrUnsafePath    = errors.New("unsafe path")
+)
+
+// Request is the input of a plugin.
+type Request struct {
+	Version   int      `json:"version"`
+	Generator string   `json:"generator"`
+	Entity    Entity   `json:"entity"`
+	Project   Project  `json:"project"`
+	Args      []string `json:"args"`
+}
+
+// Entity holds the names of the entity to generate, as used by the builtin
+// generators.
+type Entity struct {
+	Name       string `json:"name"`
+	PascalCase string `json:"pascalCase"`
+	CamelCase  string `json:"camelCase"`
+	SnakeCase  string `json:"snakeCase"`
+	KebabCase  string `json:"kebabCase"`
+	Plural     string `json:"plural"`
+	Package    string `json:"package"`
+	ImportPath string `json:"importPath"`
+	FilePath   string `json:"filePath"`
+	Type       string `json:"type"`
+}
+
+// Project describes the project the plugin generates files for.
+type Project struct {
+	Dir            string         `json:"dir"`
+	Module         string         `json:"module"`
+	StructsDir     string         `json:"structsDir"`
+	Implementation Implementation `json:"implementation"`
+	// Tags maps the keys of the tags to populate on the entity fields to
+	// their naming strategy.
+	Tags map[string]string `json:"tags"`
+}
+
+type Implementation struct {
+	PointerReceivers   bool         `json:"pointerReceivers"`
+	Constructor        bool         `json:"constructor"`
+	InterfaceAssertion bool         `json:"interfaceAssertion"`
+	FunctionalOptions  bool         `json:"functionalOptions"`
+	Dependencies       []Dependency `json:"dependencies"`
+}
+
+type Dependency struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	ImportPath string `json:"importPath,omitempty"`
+	ImportName string `json:"importName,omitempty"`
+	Default    string `json:"default,omitempty"`
+	Required   bool   `json:"required,omitempty"`
+}
+
+// File is a file generated by a plugin, at Path relative to the project
+// root. Go files are given either as Content or as the Model rendered by
+// microcli, which merges it into the existing file like its own generators.
+type File struct {
+	Path    string     `json:"path"`
+	Content string     `json:"content,omitempty"`
+	Model   *file.File `json:"model,omitempty"`
+}
+
+// Plugin is a generator running an executable. Its flags are the arguments
+// given after the entity name, passed as is in Request.Args.
+type Plugin struct {
+	name   string
+	path   string
+	args   []string
+	stderr io.Writer
+}
+
+// Find returns the plugin implementing the generator name, looking for its
+// executable on PATH.
+func Find(name string, args []string, stderr io.Writer) (Plugin, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return Plugin{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	path, err := exec.LookPath(PREFIX + name)
+	if err != nil {
+		return Plugin{}, fmt.Errorf("%w: %q, no %s%s on PATH", ErrNotFound, name, PREFIX, name)
+	}
+	return Plugin{name: name, path: path, args: args, stderr: stderr}, nil
+}
+
+func (p Plugin) Name() string { return p.name }
+
+func (p Plugin) Flags(flags *pflag.FlagSet) {}
+
+func (p Plugin) Generate(ctx context.Context, name entity.EntityName, config generator.Config) ([]generator.Output, error) {
+	dir, err := filepath.Abs(config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	input, err := json.Marshal(NewRequest(p.name, name, config, dir, p.args))
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	run := exec.CommandContext(ctx, p.path, p.args...)
+	run.Dir = dir
+	run.Stdin = bytes.NewReader(input)
+	run.Stdout = &stdout
+	run.Stderr = p.stderr
+	if err := run.Run(); err != nil {
+		return nil, fmt.Errorf("%s%s failed: %w", PREFIX, p.name, err)
+	}
+	return Decode(stdout.Bytes())
+}
+
+// NewRequest returns the request generating the entity name in the project
+// at dir.
+func NewRequest(generatorName string, name entity.EntityName, config generator.Config, dir string, args []string) Request {
+	impl := config.Implementation
+	deps := make([]Dependency, 0, len(impl.Dependencies))
+	for _, dep := range impl.Dependencies {
+		deps = append(deps, Dependency{
+			Name:       dep.Name,
+			Type:       dep.Type,
+			ImportPath: dep.Import.Path,
+			ImportName: dep.Import.Name,
+			Default:    dep.Default,
+			Required:   dep.Required,
+		})
+	}
+	if args == nil {
+		args = []string{}
+	}
+	tags := make(map[string]string, len(config.Tags))
+	for key, strategy := range config.Tags {
+		tags[key] = strategy
+	}
+
+	return Request{
+		Version:   PROTOCOL_VERSION,
+		Generator: generatorName,
+		Entity: Entity{
+			Name:       name.PascalCase(),
+			PascalCase: name.PascalCase(),
+			CamelCase:  name.CamelCase(),
+			SnakeCase:  name.SnakeCase(),
+			KebabCase:  name.KebabCase(),
+			Plural:     name.Plural().PascalCase(),
+			Package:    name.ImportName(),
+			ImportPath: name.FileImport().Path,
+			FilePath:   name.FilePath(),
+			Type:       name.Type(),
+		},
+		Project: Project{
+			Dir:        dir,
+			Module:     config.Module,
+			StructsDir: config.StructsDir,
+			Implementation: Implementation{
+				PointerReceivers:   impl.PointerReceivers,
+				Constructor:        impl.Constructor,
+				InterfaceAssertion: impl.InterfaceAssertion,
+				FunctionalOptions:  impl.FunctionalOptions,
+				Dependencies:       deps,
+			},
+			Tags: tags,
+		},
+		Args: args,
+	}
+}
+
+// Decode reads the files written by a plugin, rejecting the whole output
+// when a path leaves the project or a Go file, as given or rendered from its
+// model, does not parse, so nothing is written out of a broken output.
+func Decode(data []byte) ([]generator.Output, error) {
+	var files []File
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("%w: expected a JSON list of files: %v", ErrInvalidOutput, err)
+	}
+
+	outputs := make([]generator.Output, 0, len(files))
+	seen := make(map[string]bool)
+	for _, f := range files {
+		p, err := cleanPath(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("%w: %s written twice", ErrInvalidOutput, p)
+		}
+		seen[p] = true
+
+		content := f.Content
+		if f.Model != nil {
+			if path.Ext(p) != ".go" || f.Content != "" {
+				return nil, fmt.Errorf("%w: %s has a model, which is rendered for Go files without content only", ErrInvalidOutput, p)
+			}
+			content = f.Model.String()
+		}
+		if path.Ext(p) == ".go" {
+			if _, err := parser.ParseFile(token.NewFileSet(), p, content, parser.AllErrors); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+			}
+		}
+
+		if f.Model != nil {
+			outputs = append(outputs, generator.GoFile(filepath.FromSlash(p), *f.Model))
+		} else {
+			outputs = append(outputs, generator.RawFile(filepath.FromSlash(p), f.Content))
+		}
+	}
+	return outputs, nil
+}
+
+// cleanPath returns the slash separated path p, after checking it stays in
+// the project and out of the files microcli and git keep there.
+func cleanPath(p string) (string, error) {
+	if p == "" {
+		return "", fmt.Errorf("%w: empty path", ErrInvalidOutput)
+	}
+	slashed := filepath.ToSlash(p)
+	if path.IsAbs(slashed) || filepath.IsAbs(p) || filepath.VolumeName(p) != "" {
+		return "", fmt.Errorf("%w: %s is absolute", ErrUnsafePath, p)
+	}
+	clean := path.Clean(slashed)
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: %s is out of the project", ErrUnsafePath, p)
+	}
+	top := strings.SplitN(clean, "/", 2)[0]
+	if top == ".git" || top == path.Dir(manifest.PATH) {
+		return "", fmt.Errorf("%w: %s is reserved", ErrUnsafePath, p)
+	}
+	return clean, nil
+}
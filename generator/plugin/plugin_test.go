@@ -0,0 +1,187 @@
+package plugin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator"
+	"github.com/eduardoths/micro-cli/generator/entity"
+	"github.com/eduardoths/micro-cli/generator/plugin"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+// installPlugin writes a plugin running script and puts it first on PATH.
+func installPlugin(t *testing.T, name string, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, plugin.PREFIX+name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestFind(t *testing.T) {
+	t.Run("it should fail when the plugin is not on PATH", func(t *testing.T) {
+		installPlugin(t, "readme", "")
+		_, err := plugin.Find("microcli-test-missing", nil, nil)
+		if !errors.Is(err, plugin.ErrNotFound) {
+			utils.Error(t, plugin.ErrNotFound, err)
+		}
+	})
+
+	t.Run("it should not look up names holding a path", func(t *testing.T) {
+		installPlugin(t, "readme", "")
+		_, err := plugin.Find("../readme", nil, nil)
+		if !errors.Is(err, plugin.ErrNotFound) {
+			utils.Error(t, plugin.ErrNotFound, err)
+		}
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	name := entity.NewEntityName("XptoStruct", "src/structs", "github.com/e/svc")
+
+	t.Run("it should send the request on stdin and read the files on stdout", func(t *testing.T) {
+		dir := installPlugin(t, "readme", `cat > "$(dirname "$0")/request.json"
+printf '%s' '[{"path": "docs/xpto.md", "content": "# Xpto\n"}]'
+`)
+		p, err := plugin.Find("readme", []string{"--title", "Xpto"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		project := t.TempDir()
+		outputs, err := p.Generate(context.Background(), name, generator.Config{
+			Dir:            project,
+			Module:         "github.com/e/svc",
+			StructsDir:     "src/structs",
+			Implementation: entity.DefaultImplementationConfig(),
+		})
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if len(outputs) != 1 || outputs[0].Path != filepath.Join("docs", "xpto.md") || string(outputs[0].Content) != "# Xpto\n" {
+			utils.Error(t, "docs/xpto.md", outputs)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "request.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got plugin.Request
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Version != plugin.PROTOCOL_VERSION || got.Generator != "readme" || got.Project.Dir != project {
+			utils.Error(t, "version 1 of the readme request on "+project, got)
+		}
+		if got.Entity.SnakeCase != "xpto_struct" || got.Entity.ImportPath != "github.com/e/svc/src/structs" || got.Entity.Type != "structs.XptoStruct" {
+			utils.Error(t, "the names of XptoStruct", got.Entity)
+		}
+		if len(got.Args) != 2 || got.Args[1] != "Xpto" {
+			utils.Error(t, []string{"--title", "Xpto"}, got.Args)
+		}
+	})
+
+	t.Run("it should fail when the plugin exits with an error", func(t *testing.T) {
+		installPlugin(t, "readme", "exit 3\n")
+		p, err := plugin.Find("readme", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.Generate(context.Background(), name, generator.Config{Dir: t.TempDir()}); err == nil {
+			utils.Error(t, "exit status 3", err)
+		}
+	})
+}
+
+func TestDecode(t *testing.T) {
+	type testCase struct {
+		it      string
+		output  string
+		wantErr error
+	}
+
+	tc := []testCase{
+		{
+			it:     "it should accept files in the project",
+			output: `[{"path": "docs/../README.md", "content": "# Svc"}, {"path": "src/x.go", "content": "package x"}]`,
+		},
+		{
+			it:     "it should accept Go files given as models",
+			output: `[{"path": "src/x.go", "model": {"version": 1, "package": "x"}}]`,
+		},
+		{
+			it:      "it should reject models of another schema version",
+			output:  `[{"path": "src/x.go", "model": {"version": 2, "package": "x"}}]`,
+			wantErr: plugin.ErrInvalidOutput,
+		},
+		{
+			it:      "it should reject models rendering code that does not parse",
+			output:  `[{"path": "src/x.go", "model": {"version": 1, "package": "x", "funcs": [{"func": {"name": "X"}, "codeLines": ["if {"]}]}}]`,
+			wantErr: plugin.ErrInvalidOutput,
+		},
+		{
+			it:      "it should reject models of files other than Go ones",
+			output:  `[{"path": "README.md", "model": {"version": 1, "package": "x"}}]`,
+			wantErr: plugin.ErrInvalidOutput,
+		},
+		{
+			it:      "it should reject output that is not a list of files",
+			output:  `{"path": "README.md"}`,
+			wantErr: plugin.ErrInvalidOutput,
+		},
+		{
+			it:      "it should reject empty paths",
+			output:  `[{"path": "", "content": ""}]`,
+			wantErr: plugin.ErrInvalidOutput,
+		},
+		{
+			it:      "it should reject absolute paths",
+			output:  `[{"path": "/etc/passwd", "content": ""}]`,
+			wantErr: plugin.ErrUnsafePath,
+		},
+		{
+			it:      "it should reject paths out of the project",
+			output:  `[{"path": "src/../../x.go", "content": "package x"}]`,
+			wantErr: plugin.ErrUnsafePath,
+		},
+		{
+			it:      "it should reject the manifest",
+			output:  `[{"path": ".microcli/manifest.json", "content": "{}"}]`,
+			wantErr: plugin.ErrUnsafePath,
+		},
+		{
+			it:      "it should reject the git directory",
+			output:  `[{"path": ".git/config", "content": ""}]`,
+			wantErr: plugin.ErrUnsafePath,
+		},
+		{
+			it:      "it should reject files written twice",
+			output:  `[{"path": "a.md", "content": ""}, {"path": "./a.md", "content": ""}]`,
+			wantErr: plugin.ErrInvalidOutput,
+		},
+		{
+			it:      "it should reject Go files that do not parse",
+			output:  `[{"path": "x.go", "content": "package x\nfunc {"}]`,
+			wantErr: plugin.ErrInvalidOutput,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			_, err := plugin.Decode([]byte(c.output))
+			if !errors.Is(err, c.wantErr) {
+				utils.Error(t, c.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package proto
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type File struct {
+	Package  string
+	Options  []Option
+	Imports  []string
+	Services []Service
+	Messages []Message
+}
+
+func (f File) String() string {
+	var sb strings.Builder
+	sb.WriteString("syntax = \"proto3\";\n")
+	if f.Package != "" {
+		sb.WriteString("\npackage " + f.Package + ";\n")
+	}
+	if len(f.Options) > 0 {
+		sb.WriteString("\n")
+	}
+	for i := range f.Options {
+		sb.WriteString(f.Options[i].String())
+	}
+	imports := uniqueSorted(f.Imports)
+	if len(imports) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, imp := range imports {
+		sb.WriteString(fmt.Sprintf("import %q;\n", imp))
+	}
+	for i := range f.Services {
+		sb.WriteString(f.Services[i].String())
+	}
+	for i := range f.Messages {
+		sb.WriteString(f.Messages[i].String())
+	}
+	return sb.String()
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+type Option struct {
+	Name  string
+	Value string
+}
+
+func (o Option) String() string {
+	return fmt.Sprintf("option %s = %q;\n", o.Name, o.Value)
+}
+
+type Service struct {
+	Name string
+	RPCs []RPC
+}
+
+func (s Service) String() string {
+	var sb strings.Builder
+	sb.WriteString("\nservice " + s.Name + " {")
+	if len(s.RPCs) > 0 {
+		sb.WriteString("\n")
+	}
+	for i := range s.RPCs {
+		sb.WriteString("  " + s.RPCs[i].String() + "\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+type RPC struct {
+	Name     string
+	Request  string
+	Response string
+}
+
+func (r RPC) String() string {
+	return fmt.Sprintf("rpc %s(%s) returns (%s);", r.Name, r.Request, r.Response)
+}
+
+type Message struct {
+	Name   string
+	Fields []Field
+}
+
+func (m Message) String() string {
+	var sb strings.Builder
+	sb.WriteString("\nmessage " + m.Name + " {")
+	if len(m.Fields) > 0 {
+		sb.WriteString("\n")
+	}
+	for i := range m.Fields {
+		sb.WriteString("  " + m.Fields[i].String() + "\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+type Field struct {
+	Name     string
+	Type     string
+	Number   int
+	Repeated bool
+	Optional bool
+}
+
+func (f Field) String() string {
+	var sb strings.Builder
+	if f.Repeated {
+		sb.WriteString("repeated ")
+	} else if f.Optional {
+		sb.WriteString("optional ")
+	}
+	sb.WriteString(fmt.Sprintf("%s %s = %d;", f.Type, f.Name, f.Number))
+	return sb.String()
+}
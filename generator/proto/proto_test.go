@@ -0,0 +1,95 @@
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/proto"
+)
+
+func TestFile_String(t *testing.T) {
+	type testCase struct {
+		it   string
+		file proto.File
+		want string
+	}
+
+	tc := []testCase{
+		{
+			it:   "should return a file that has only the syntax",
+			file: proto.File{},
+			want: "syntax = \"proto3\";\n",
+		},
+		{
+			it: "should return a file with package, options and sorted unique imports",
+			file: proto.File{
+				Package: "xpto",
+				Options: []proto.Option{
+					{Name: "go_package", Value: "github.com/eduardoths/xpto;xptopb"},
+				},
+				Imports: []string{
+					"google/protobuf/timestamp.proto",
+					"google/protobuf/empty.proto",
+					"google/protobuf/timestamp.proto",
+				},
+			},
+			want: "syntax = \"proto3\";\n\n" +
+				"package xpto;\n\n" +
+				"option go_package = \"github.com/eduardoths/xpto;xptopb\";\n\n" +
+				"import \"google/protobuf/empty.proto\";\n" +
+				"import \"google/protobuf/timestamp.proto\";\n",
+		},
+		{
+			it: "should return a file with a service",
+			file: proto.File{
+				Services: []proto.Service{
+					{
+						Name: "XptoService",
+						RPCs: []proto.RPC{
+							{Name: "GetXpto", Request: "GetXptoRequest", Response: "Xpto"},
+						},
+					},
+					{Name: "EmptyService"},
+				},
+			},
+			want: "syntax = \"proto3\";\n\n" +
+				"service XptoService {\n" +
+				"  rpc GetXpto(GetXptoRequest) returns (Xpto);\n" +
+				"}\n\n" +
+				"service EmptyService {}\n",
+		},
+		{
+			it: "should return a file with messages",
+			file: proto.File{
+				Messages: []proto.Message{
+					{
+						Name: "Xpto",
+						Fields: []proto.Field{
+							{Name: "id", Type: "string", Number: 1},
+							{Name: "tags", Type: "string", Number: 2, Repeated: true},
+							{Name: "nickname", Type: "string", Number: 3, Optional: true},
+						},
+					},
+					{Name: "Empty"},
+				},
+			},
+			want: "syntax = \"proto3\";\n\n" +
+				"message Xpto {\n" +
+				"  string id = 1;\n" +
+				"  repeated string tags = 2;\n" +
+				"  optional string nickname = 3;\n" +
+				"}\n\n" +
+				"message Empty {}\n",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			actual := c.file.String()
+			if c.want != actual {
+				t.Errorf("File.String() failed, want: \n%s\ngot\n%s", c.want, actual)
+				t.Logf("\nTest:%s", c.it)
+				t.FailNow()
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package regions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/file"
+)
+
+const (
+	HEADER          = "// Code generated by microcli. Edit only between the microcli:begin and microcli:end markers."
+	CHECKSUM_PREFIX = "// microcli:checksum "
+)
+
+var (
+	ErrConflict       = errors.New("regeneration conflict")
+	ErrInvalidRegions = errors.New("invalid microcli regions")
+)
+
+type region struct {
+	key   string
+	hash  string
+	begin int
+	end   int
+}
+
+type document struct {
+	lines    []string
+	regions  []region
+	checksum string
+}
+
+func parse(src []byte) (document, error) {
+	doc := document{lines: strings.Split(string(src), "\n")}
+	current := -1
+	for i, line := range doc.lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, CHECKSUM_PREFIX):
+			doc.checksum = strings.TrimPrefix(trimmed, CHECKSUM_PREFIX)
+		case strings.HasPrefix(trimmed, file.REGION_BEGIN):
+			if current >= 0 {
+				return document{}, fmt.Errorf("%w: region %s opened on line %d is not closed",
+					ErrInvalidRegions, doc.regions[current].key, doc.regions[current].begin+1)
+			}
+			fields := strings.Fields(strings.TrimPrefix(trimmed, file.REGION_BEGIN))
+			if len(fields) == 0 {
+				return document{}, fmt.Errorf("%w: region on line %d has no name", ErrInvalidRegions, i+1)
+			}
+			r := region{key: fields[0], begin: i}
+			if len(fields) > 1 {
+				r.hash = fields[1]
+			}
+			doc.regions = append(doc.regions, r)
+			current = len(doc.regions) - 1
+		case trimmed == file.REGION_END:
+			if current < 0 {
+				return document{}, fmt.Errorf("%w: line %d closes no region", ErrInvalidRegions, i+1)
+			}
+			doc.regions[current].end = i
+			current = -1
+		}
+	}
+	if current >= 0 {
+		return document{}, fmt.Errorf("%w: region %s opened on line %d is not closed",
+			ErrInvalidRegions, doc.regions[current].key, doc.regions[current].begin+1)
+	}
+	return doc, nil
+}
+
+func (doc document) content(r region) []string {
+	return doc.lines[r.begin+1 : r.end]
+}
+
+func (doc document) find(key string) (region, bool) {
+	for _, r := range doc.regions {
+		if r.key == key {
+			return r, true
+		}
+	}
+	return region{}, false
+}
+
+// outsideHash hashes the lines out of the regions, which only the generator
+// is expected to change.
+func (doc document) outsideHash() string {
+	outside := make([]string, 0, len(doc.lines))
+	next := 0
+	for i, line := range doc.lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == HEADER || strings.HasPrefix(trimmed, strings.TrimSpace(CHECKSUM_PREFIX)):
+		case next < len(doc.regions) && i == doc.regions[next].begin:
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			outside = append(outside, indent+file.REGION_BEGIN+doc.regions[next].key)
+		case next < len(doc.regions) && i > doc.regions[next].begin && i < doc.regions[next].end:
+		default:
+			if next < len(doc.regions) && i == doc.regions[next].end {
+				next++
+			}
+			outside = append(outside, line)
+		}
+	}
+	return hash(outside)
+}
+
+func hash(lines []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// IsManaged reports whether src was written by Stamp.
+func IsManaged(src []byte) bool {
+	return bytes.Contains(src, []byte(CHECKSUM_PREFIX))
+}
+
+// Pristine reports whether src was written by Stamp and was not edited
+// since, neither in nor out of its regions.
+func Pristine(src []byte) bool {
+	if !IsManaged(src) {
+		return false
+	}
+	doc, err := parse(src)
+	if err != nil || doc.outsideHash() != doc.checksum {
+		return false
+	}
+	for _, r := range doc.regions {
+		if hash(doc.content(r)) != r.hash {
+			return false
+		}
+	}
+	return true
+}
+
+// Stamp adds the generated header to src and records the checksum of every
+// region, so that Merge can later tell the regions edited by users apart.
+func Stamp(src []byte) ([]byte, error) {
+	doc, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range doc.regions {
+		line := doc.lines[r.begin]
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		doc.lines[r.begin] = indent + file.REGION_BEGIN + r.key + " " + hash(doc.content(r))
+	}
+	doc.lines = append([]string{HEADER, CHECKSUM_PREFIX, ""}, doc.lines...)
+	for i := range doc.regions {
+		doc.regions[i].begin += 3
+		doc.regions[i].end += 3
+	}
+	doc.lines[1] = CHECKSUM_PREFIX + doc.outsideHash()
+	return []byte(strings.Join(doc.lines, "\n")), nil
+}
+
+// Restamp stamps after, a rewrite of before keeping its regions in the same
+// order, so that the regions and the code out of them that were not edited
+// in before are still seen as generated.
+func Restamp(before []byte, after []byte) ([]byte, error) {
+	prev, err := parse(before)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parse(after)
+	if err != nil {
+		return nil, err
+	}
+	if len(prev.regions) != len(doc.regions) {
+		return nil, fmt.Errorf("%w: rewrite has %d regions, expected %d", ErrInvalidRegions, len(doc.regions), len(prev.regions))
+	}
+
+	for i, r := range doc.regions {
+		hashed := prev.regions[i].hash
+		if hash(prev.content(prev.regions[i])) == hashed {
+			hashed = hash(doc.content(r))
+		}
+		line := doc.lines[r.begin]
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		doc.lines[r.begin] = indent + file.REGION_BEGIN + r.key + " " + hashed
+	}
+	if prev.outsideHash() == prev.checksum {
+		for i, line := range doc.lines {
+			if strings.HasPrefix(strings.TrimSpace(line), strings.TrimSpace(CHECKSUM_PREFIX)) {
+				doc.lines[i] = CHECKSUM_PREFIX + doc.outsideHash()
+				break
+			}
+		}
+	}
+	return []byte(strings.Join(doc.lines, "\n")), nil
+}
+
+// Merge carries the regions edited by users from existing into generated,
+// both written by Stamp. Edits out of the regions and edited regions that no
+// longer exist are reported as conflicts.
+func Merge(generated []byte, existing []byte) ([]byte, error) {
+	gen, err := parse(generated)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := parse(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]string, 0)
+	if prev.outsideHash() != prev.checksum {
+		conflicts = append(conflicts, "code out of the microcli regions was edited")
+	}
+
+	preserved := make(map[string][]string)
+	for _, r := range prev.regions {
+		content := prev.content(r)
+		if hash(content) == r.hash {
+			continue
+		}
+		if _, ok := gen.find(r.key); !ok {
+			conflicts = append(conflicts, "edited region "+r.key+" is no longer generated")
+			continue
+		}
+		preserved[r.key] = content
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, strings.Join(conflicts, "; "))
+	}
+
+	merged := make([]string, 0, len(gen.lines))
+	next := 0
+	for _, r := range gen.regions {
+		merged = append(merged, gen.lines[next:r.begin+1]...)
+		if content, ok := preserved[r.key]; ok {
+			merged = append(merged, content...)
+		} else {
+			merged = append(merged, gen.content(r)...)
+		}
+		next = r.end
+	}
+	merged = append(merged, gen.lines[next:]...)
+	return []byte(strings.Join(merged, "\n")), nil
+}
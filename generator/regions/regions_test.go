@@ -0,0 +1,192 @@
+package regions_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/regions"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+const source = "package xpto\n\n" +
+	"func Get() error {\n" +
+	"\t// microcli:begin Get\n" +
+	"\tpanic(\"not implemented\")\n" +
+	"\t// microcli:end\n" +
+	"}\n"
+
+func stamp(t *testing.T, src string) string {
+	t.Helper()
+	stamped, err := regions.Stamp([]byte(src))
+	if err != nil {
+		t.Fatalf("Stamp failed: %v", err)
+	}
+	return string(stamped)
+}
+
+func TestStamp(t *testing.T) {
+	t.Run("it should add the header and the region checksums", func(t *testing.T) {
+		stamped := stamp(t, source)
+		if !strings.HasPrefix(stamped, regions.HEADER+"\n"+regions.CHECKSUM_PREFIX) {
+			utils.Error(t, "header and checksum", stamped)
+		}
+		if !strings.Contains(stamped, "\t// microcli:begin Get ") {
+			utils.Error(t, "hashed begin marker", stamped)
+		}
+		if !regions.IsManaged([]byte(stamped)) {
+			utils.Error(t, true, false)
+		}
+	})
+
+	t.Run("it should fail on unbalanced markers", func(t *testing.T) {
+		_, err := regions.Stamp([]byte(strings.Replace(source, "\t// microcli:end\n", "", 1)))
+		if !errors.Is(err, regions.ErrInvalidRegions) {
+			utils.Error(t, regions.ErrInvalidRegions, err)
+		}
+	})
+
+	t.Run("it should leave unmanaged files unrecognized", func(t *testing.T) {
+		if regions.IsManaged([]byte(source)) {
+			utils.Error(t, false, true)
+		}
+	})
+}
+
+func TestMerge(t *testing.T) {
+	type testCase struct {
+		it        string
+		generated string
+		existing  func(string) string
+		want      []string
+		wantErr   error
+	}
+
+	regenerated := source + "\nfunc Delete() error {\n" +
+		"\t// microcli:begin Delete\n" +
+		"\treturn nil\n" +
+		"\t// microcli:end\n" +
+		"}\n"
+	edit := func(s string) string {
+		return strings.Replace(s, "\tpanic(\"not implemented\")", "\treturn nil", 1)
+	}
+
+	tc := []testCase{
+		{
+			it:        "should take the generated content of untouched regions",
+			generated: strings.Replace(regenerated, "not implemented", "todo", 1),
+			existing:  func(s string) string { return s },
+			want:      []string{"\tpanic(\"todo\")", "func Delete() error {"},
+		},
+		{
+			it:        "should preserve the regions edited by users",
+			generated: regenerated,
+			existing:  edit,
+			want:      []string{"func Get() error {\n\t// microcli:begin Get ", "\treturn nil\n\t// microcli:end\n}\n\nfunc Delete"},
+		},
+		{
+			it:        "should report edits out of the regions",
+			generated: regenerated,
+			existing: func(s string) string {
+				return strings.Replace(s, "func Get() error {", "func Get() (err error) {", 1)
+			},
+			wantErr: regions.ErrConflict,
+		},
+		{
+			it:        "should report edited regions that are no longer generated",
+			generated: strings.Replace(regenerated, "// microcli:begin Get", "// microcli:begin List", 1),
+			existing:  edit,
+			wantErr:   regions.ErrConflict,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			existing := c.existing(stamp(t, source))
+			merged, err := regions.Merge([]byte(stamp(t, c.generated)), []byte(existing))
+			if !errors.Is(err, c.wantErr) {
+				utils.Error(t, c.wantErr, err)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(string(merged), want) {
+					utils.Error(t, want, string(merged))
+				}
+			}
+		})
+	}
+
+	t.Run("it should keep preserved regions across regenerations", func(t *testing.T) {
+		merged, err := regions.Merge([]byte(stamp(t, source)), []byte(edit(stamp(t, source))))
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		again, err := regions.Merge([]byte(stamp(t, source)), merged)
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		if string(again) != string(merged) {
+			utils.Error(t, string(merged), string(again))
+		}
+	})
+}
+
+func TestPristine(t *testing.T) {
+	type testCase struct {
+		it   string
+		edit func(string) string
+		want bool
+	}
+
+	tc := []testCase{
+		{it: "should accept files as stamped", edit: func(s string) string { return s }, want: true},
+		{
+			it:   "should reject files edited in a region",
+			edit: func(s string) string { return strings.Replace(s, "panic(\"not implemented\")", "return nil", 1) },
+		},
+		{
+			it:   "should reject files edited out of the regions",
+			edit: func(s string) string { return s + "\n// edited\n" },
+		},
+		{
+			it:   "should reject files not written by Stamp",
+			edit: func(string) string { return source },
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			if got := regions.Pristine([]byte(c.edit(stamp(t, source)))); got != c.want {
+				utils.Error(t, c.want, got)
+			}
+		})
+	}
+}
+
+func TestRestamp(t *testing.T) {
+	rename := func(s string) string { return strings.ReplaceAll(s, "Get", "Find") }
+
+	t.Run("it should keep rewritten files generated", func(t *testing.T) {
+		restamped, err := regions.Restamp([]byte(stamp(t, source)), []byte(rename(stamp(t, source))))
+		if err != nil {
+			t.Fatalf("Restamp failed: %v", err)
+		}
+		if want := stamp(t, rename(source)); string(restamped) != want {
+			utils.Error(t, want, string(restamped))
+		}
+	})
+
+	t.Run("it should keep edited regions edited", func(t *testing.T) {
+		edited := strings.Replace(stamp(t, source), "panic(\"not implemented\")", "return nil", 1)
+		restamped, err := regions.Restamp([]byte(edited), []byte(rename(edited)))
+		if err != nil {
+			t.Fatalf("Restamp failed: %v", err)
+		}
+		merged, err := regions.Merge([]byte(stamp(t, rename(source))), restamped)
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		if !strings.Contains(string(merged), "\treturn nil\n") {
+			utils.Error(t, "the edited region", string(merged))
+		}
+	})
+}
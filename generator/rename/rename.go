@@ -0,0 +1,253 @@
+package rename
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+)
+
+var ErrInvalidName = errors.New("invalid entity name")
+
+// packageSuffixes are appended to package names to alias their imports.
+var packageSuffixes = []string{"", "pb", "repository", "service", "handler", "server"}
+
+type boundary int
+
+const (
+	// identWord matches anywhere, as long as no lower case letter follows.
+	identWord boundary = iota
+	// identStart matches at the start of an identifier only.
+	identStart
+	// textWord matches between non alphanumeric characters.
+	textWord
+)
+
+type form struct {
+	old      string
+	new      string
+	boundary boundary
+}
+
+// Renamer computes the identifiers, texts and paths of an entity renamed,
+// leaving the names of the other entities untouched.
+type Renamer struct {
+	from     entity.EntityName
+	to       entity.EntityName
+	idents   []form
+	texts    []form
+	packages map[string]string
+}
+
+// New returns the renamer of the entity from to the entity to. others are
+// the names of the other entities of the project, protected from being
+// renamed when they start with from.
+func New(from string, to string, others []string) (Renamer, error) {
+	for _, name := range []string{from, to} {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return Renamer{}, fmt.Errorf("%w: %q must be an exported Go identifier", ErrInvalidName, name)
+		}
+	}
+	r := Renamer{
+		from:     entity.NewEntityName(from, "", ""),
+		to:       entity.NewEntityName(to, "", ""),
+		packages: make(map[string]string),
+	}
+	if r.from.PascalCase() == r.to.PascalCase() {
+		return Renamer{}, fmt.Errorf("%w: %s is already named %s", ErrInvalidName, from, to)
+	}
+
+	for _, other := range others {
+		name := entity.NewEntityName(other, "", "")
+		if name.PascalCase() != r.from.PascalCase() {
+			r.addForms(name, name)
+		}
+	}
+	r.addForms(r.from, r.to)
+	for _, suffix := range packageSuffixes {
+		r.packages[packageName(r.from)+suffix] = packageName(r.to) + suffix
+	}
+
+	// longest forms first, so that protected names win over the renamed one
+	byLength := func(forms []form) func(i, j int) bool {
+		return func(i, j int) bool { return len(forms[i].old) > len(forms[j].old) }
+	}
+	sort.SliceStable(r.idents, byLength(r.idents))
+	sort.SliceStable(r.texts, byLength(r.texts))
+	return r, nil
+}
+
+func (r *Renamer) addForms(from entity.EntityName, to entity.EntityName) {
+	for _, names := range [][2]entity.EntityName{{from.Plural(), to.Plural()}, {from, to}} {
+		old, new := names[0], names[1]
+		r.idents = append(r.idents,
+			form{old.PascalCase(), new.PascalCase(), identWord},
+			form{old.CamelCase(), new.CamelCase(), identStart},
+		)
+		r.texts = append(r.texts,
+			form{old.SnakeCase(), new.SnakeCase(), textWord},
+			form{old.KebabCase(), new.KebabCase(), textWord},
+			form{words(old), words(new), textWord},
+		)
+	}
+	r.texts = append(r.texts, form{packageName(from), packageName(to), textWord})
+}
+
+// packageName is the name of the packages generated for the entity.
+func packageName(name entity.EntityName) string {
+	return entity.NewEntityName(name.PascalCase(), name.SnakeCase(), "").ImportName()
+}
+
+func words(name entity.EntityName) string {
+	return strings.ReplaceAll(name.SnakeCase(), "_", " ")
+}
+
+func (r Renamer) From() entity.EntityName {
+	return r.from
+}
+
+func (r Renamer) To() entity.EntityName {
+	return r.to
+}
+
+// Ident renames the entity in a Go identifier.
+func (r Renamer) Ident(name string) string {
+	if pkg, ok := r.packages[name]; ok {
+		return pkg
+	}
+	return replace(name, r.idents)
+}
+
+// Text renames the entity in a string: its snake case, kebab case and lower
+// case words, as in paths, routes and messages, and the identifiers in it.
+func (r Renamer) Text(s string) string {
+	return replace(r.comment(s), r.texts)
+}
+
+// comment renames the identifiers of s holding upper case letters, leaving
+// the plain words alone.
+func (r Renamer) comment(s string) string {
+	var sb strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := s[start:end]
+		if strings.ToLower(word) != word {
+			word = r.Ident(word)
+		}
+		sb.WriteString(word)
+		start = -1
+	}
+	for i, c := range s {
+		if isIdentRune(c) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+		sb.WriteRune(c)
+	}
+	flush(len(s))
+	return sb.String()
+}
+
+// Path renames the entity in the elements of a slash separated path.
+func (r Renamer) Path(p string) string {
+	elems := strings.Split(p, "/")
+	for i := range elems {
+		elems[i] = replace(elems[i], r.texts)
+	}
+	return path.Join(elems...)
+}
+
+func typeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return typeName(expr.X)
+	case *ast.IndexExpr:
+		return typeName(expr.X)
+	case *ast.IndexListExpr:
+		return typeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// literal renames the entity in the content of a string literal, keeping
+// its quotes.
+func (r Renamer) literal(value string) string {
+	if strings.HasPrefix(value, "`") {
+		return "`" + r.Text(strings.Trim(value, "`")) + "`"
+	}
+	s, err := strconv.Unquote(value)
+	if err != nil {
+		return value
+	}
+	renamed := r.Text(s)
+	if renamed == s {
+		return value
+	}
+	return strconv.Quote(renamed)
+}
+
+// replace substitutes the forms found in s in a single pass, trying the
+// forms in order at each position.
+func replace(s string, forms []form) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		matched := false
+		for _, f := range forms {
+			if f.old == "" || !strings.HasPrefix(s[i:], f.old) || !f.matches(s, i) {
+				continue
+			}
+			sb.WriteString(f.new)
+			i += len(f.old)
+			matched = true
+			break
+		}
+		if !matched {
+			c, size := utf8.DecodeRuneInString(s[i:])
+			sb.WriteRune(c)
+			i += size
+		}
+	}
+	return sb.String()
+}
+
+func (f form) matches(s string, start int) bool {
+	end := start + len(f.old)
+	before, _ := utf8.DecodeLastRuneInString(s[:start])
+	after, _ := utf8.DecodeRuneInString(s[end:])
+	hasBefore, hasAfter := start > 0, end < len(s)
+
+	switch f.boundary {
+	case identStart:
+		if hasBefore && isIdentRune(before) {
+			return false
+		}
+		return !hasAfter || !unicode.IsLower(after)
+	case textWord:
+		return (!hasBefore || !isAlphanumeric(before)) && (!hasAfter || !isAlphanumeric(after))
+	}
+	return !hasAfter || !unicode.IsLower(after)
+}
+
+func isIdentRune(c rune) bool {
+	return c == '_' || isAlphanumeric(c)
+}
+
+func isAlphanumeric(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
@@ -0,0 +1,232 @@
+package rename_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/rename"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+func newRenamer(t *testing.T) rename.Renamer {
+	t.Helper()
+	r, err := rename.New("XptoStruct", "Order", []string{"XptoStruct", "XptoStructName"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return r
+}
+
+func TestNew(t *testing.T) {
+	type testCase struct {
+		it   string
+		from string
+		to   string
+	}
+
+	tc := []testCase{
+		{it: "should reject unexported names", from: "XptoStruct", to: "order"},
+		{it: "should reject invalid identifiers", from: "Xpto-Struct", to: "Order"},
+		{it: "should reject renames to the same name", from: "XptoStruct", to: "XptoStruct"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			if _, err := rename.New(c.from, c.to, nil); !errors.Is(err, rename.ErrInvalidName) {
+				utils.Error(t, rename.ErrInvalidName, err)
+			}
+		})
+	}
+}
+
+func TestRenamer(t *testing.T) {
+	type testCase struct {
+		it     string
+		rename func(rename.Renamer, string) string
+		input  string
+		want   string
+	}
+
+	ident := rename.Renamer.Ident
+	text := rename.Renamer.Text
+	path := rename.Renamer.Path
+	tc := []testCase{
+		{it: "should rename types", rename: ident, input: "XptoStructRepository", want: "OrderRepository"},
+		{it: "should rename constructors", rename: ident, input: "NewXptoStructService", want: "NewOrderService"},
+		{it: "should rename variables", rename: ident, input: "xptoStruct", want: "order"},
+		{it: "should rename plural names", rename: ident, input: "ListXptoStructsResponse", want: "ListOrdersResponse"},
+		{it: "should rename unexported types", rename: ident, input: "fakeXptoStructService", want: "fakeOrderService"},
+		{it: "should rename packages", rename: ident, input: "xptostructpb", want: "orderpb"},
+		{it: "should keep other entities", rename: ident, input: "XptoStructNameRepository", want: "XptoStructNameRepository"},
+		{it: "should keep longer words", rename: ident, input: "XptoStructure", want: "XptoStructure"},
+		{it: "should rename routes", rename: text, input: "GET /xpto-structs/{id}", want: "GET /orders/{id}"},
+		{it: "should rename messages", rename: text, input: "xpto struct not found", want: "order not found"},
+		{it: "should rename import paths", rename: text, input: "github.com/e/svc/src/services/xpto_struct", want: "github.com/e/svc/src/services/order"},
+		{it: "should rename paths", rename: path, input: "src/handlers/xpto_struct/xpto_struct_handler_test.go", want: "src/handlers/order/order_handler_test.go"},
+		{it: "should keep the paths of other entities", rename: path, input: "src/structs/xpto_struct_name.go", want: "src/structs/xpto_struct_name.go"},
+	}
+
+	r := newRenamer(t)
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			if got := c.rename(r, c.input); got != c.want {
+				utils.Error(t, c.want, got)
+			}
+		})
+	}
+}
+
+func TestRenamer_Sources(t *testing.T) {
+	t.Run("it should rename the entity objects and their uses", func(t *testing.T) {
+		files := map[string]string{
+			"go.mod": "module github.com/e/svc\n\ngo 1.19\n",
+			"src/repositories/xpto_struct/xpto_struct_repository.go": "package xptostruct\n\n" +
+				"type XptoStructRepository interface {\n" +
+				"\tDelete(id string) error\n" +
+				"}\n",
+			"src/services/xpto_struct/xpto_struct_service.go": "package xptostruct\n\n" +
+				"import (\n" +
+				"\t\"errors\"\n\n" +
+				"\txptostruct \"github.com/e/svc/src/repositories/xpto_struct\"\n" +
+				")\n\n" +
+				"// XptoStructService handles xpto structs.\n" +
+				"type XptoStructService struct {\n" +
+				"\trepository xptostruct.XptoStructRepository\n" +
+				"}\n\n" +
+				"func NewXptoStructService(repository xptostruct.XptoStructRepository) *XptoStructService {\n" +
+				"\txss := &XptoStructService{repository: repository}\n" +
+				"\treturn xss\n" +
+				"}\n\n" +
+				"func (xss *XptoStructService) Delete(xptoStruct string) error {\n" +
+				"\tif xptoStruct == \"\" {\n" +
+				"\t\treturn errors.New(\"xpto struct not found\")\n" +
+				"\t}\n" +
+				"\treturn xss.repository.Delete(xptoStruct)\n" +
+				"}\n",
+			"src/handlers/xpto_struct/xpto_struct_handler.go": "package xptostruct\n\n" +
+				"import (\n" +
+				"\t\"net/http\"\n\n" +
+				"\t\"github.com/e/svc/src/services/xpto_struct\"\n" +
+				")\n\n" +
+				"type XptoStructHandler struct {\n" +
+				"\tservice *xptostruct.XptoStructService\n" +
+				"}\n\n" +
+				"func (xsh *XptoStructHandler) Delete(w http.ResponseWriter, r *http.Request) {\n" +
+				"\t_ = xsh.service.Delete(r.URL.Query().Get(\"id\"))\n" +
+				"}\n",
+			"main.go": "package main\n\n" +
+				"import (\n" +
+				"\t\"fmt\"\n\n" +
+				"\tsvc \"github.com/e/svc/src/services/xpto_struct\"\n" +
+				")\n\n" +
+				"// xptoStruct is the xpto struct service.\n" +
+				"var xptoStruct = svc.NewXptoStructService(nil)\n\n" +
+				"func main() {\n" +
+				"\tfmt.Println(\"xpto struct\", xptoStruct)\n" +
+				"}\n",
+		}
+		want := map[string]string{
+			"src/repositories/xpto_struct/xpto_struct_repository.go": "package order\n\n" +
+				"type OrderRepository interface {\n" +
+				"\tDelete(id string) error\n" +
+				"}\n",
+			"src/services/xpto_struct/xpto_struct_service.go": "package order\n\n" +
+				"import (\n" +
+				"\t\"errors\"\n\n" +
+				"\torderrepository \"github.com/e/svc/src/repositories/order\"\n" +
+				")\n\n" +
+				"// OrderService handles xpto structs.\n" +
+				"type OrderService struct {\n" +
+				"\trepository orderrepository.OrderRepository\n" +
+				"}\n\n" +
+				"func NewOrderService(repository orderrepository.OrderRepository) *OrderService {\n" +
+				"\tos := &OrderService{repository: repository}\n" +
+				"\treturn os\n" +
+				"}\n\n" +
+				"func (os *OrderService) Delete(order string) error {\n" +
+				"\tif order == \"\" {\n" +
+				"\t\treturn errors.New(\"order not found\")\n" +
+				"\t}\n" +
+				"\treturn os.repository.Delete(order)\n" +
+				"}\n",
+			"src/handlers/xpto_struct/xpto_struct_handler.go": "package order\n\n" +
+				"import (\n" +
+				"\t\"net/http\"\n\n" +
+				"\t\"github.com/e/svc/src/services/order\"\n" +
+				")\n\n" +
+				"type OrderHandler struct {\n" +
+				"\tservice *order.OrderService\n" +
+				"}\n\n" +
+				"func (oh *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {\n" +
+				"\t_ = oh.service.Delete(r.URL.Query().Get(\"id\"))\n" +
+				"}\n",
+			"main.go": "package main\n\n" +
+				"import (\n" +
+				"\t\"fmt\"\n\n" +
+				"\tsvc \"github.com/e/svc/src/services/order\"\n" +
+				")\n\n" +
+				"// xptoStruct is the xpto struct service.\n" +
+				"var xptoStruct = svc.NewOrderService(nil)\n\n" +
+				"func main() {\n" +
+				"\tfmt.Println(\"xpto struct\", xptoStruct)\n" +
+				"}\n",
+		}
+
+		dir := t.TempDir()
+		for name, content := range files {
+			fullPath := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := newRenamer(t).Sources(dir, "github.com/e/svc")
+		if err != nil {
+			t.Fatalf("Sources failed: %v", err)
+		}
+		if len(got) != len(want) {
+			utils.Error(t, len(want), len(got))
+		}
+		for name, content := range want {
+			if string(got[name]) != content {
+				t.Errorf("Renamer.Sources() failed for %s, want: \n%s\ngot\n%s", name, content, got[name])
+			}
+		}
+	})
+
+	t.Run("it should keep the identifiers of other packages", func(t *testing.T) {
+		dir := t.TempDir()
+		src := "package request\n\n" +
+			"import \"net/http\"\n\n" +
+			"type RequestHandler struct{}\n\n" +
+			"func (rh *RequestHandler) Get(w http.ResponseWriter, r *http.Request) {}\n"
+		fullPath := filepath.Join(dir, "src", "handlers", "request", "request_handler.go")
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		r, err := rename.New("Request", "Ticket", nil)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+
+		got, err := r.Sources(dir, "github.com/e/svc")
+		if err != nil {
+			t.Fatalf("Sources failed: %v", err)
+		}
+		want := "package ticket\n\n" +
+			"import \"net/http\"\n\n" +
+			"type TicketHandler struct{}\n\n" +
+			"func (th *TicketHandler) Get(w http.ResponseWriter, r *http.Request) {}\n"
+		if content := string(got["src/handlers/request/request_handler.go"]); content != want {
+			t.Errorf("Renamer.Sources() failed, want: \n%s\ngot\n%s", want, content)
+		}
+	})
+}
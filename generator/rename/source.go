@@ -0,0 +1,376 @@
+package rename
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eduardoths/micro-cli/generator/entity"
+)
+
+// project holds the Go files of a module, parsed once and type-checked so
+// that identifiers are renamed by the object they refer to.
+type project struct {
+	dir    string
+	module string
+	fset   *token.FileSet
+	// files are the parsed files by directory, relative to the module root.
+	files    map[string][]*ast.File
+	packages map[string]*types.Package
+	checking map[string]bool
+	info     *types.Info
+	stdlib   types.Importer
+	source   types.ImporterFrom
+}
+
+// Sources renames the entity in the Go files of the module at dir. The
+// package is type-checked: only the objects declared in the files of the
+// entity, the ones moved by Path, are renamed along with their uses, so that
+// the identifiers of the other packages, such as http.Request when renaming
+// Request, are left alone. String literals and comments are only renamed in
+// the files of the entity, and import aliases are kept. The rewritten
+// sources are returned by their slash separated path relative to dir.
+func (r Renamer) Sources(dir string, module string) (map[string][]byte, error) {
+	fset := token.NewFileSet()
+	p := &project{
+		dir:      dir,
+		module:   module,
+		fset:     fset,
+		files:    make(map[string][]*ast.File),
+		packages: make(map[string]*types.Package),
+		checking: make(map[string]bool),
+		info: &types.Info{
+			Defs:      make(map[*ast.Ident]types.Object),
+			Uses:      make(map[*ast.Ident]types.Object),
+			Implicits: make(map[ast.Node]types.Object),
+		},
+		stdlib: importer.ForCompiler(fset, "gc", nil),
+		source: importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+	}
+	sources, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	p.check()
+
+	rewritten := make(map[string][]byte)
+	for rel, src := range sources {
+		f := p.file(rel)
+		r.rewrite(p, f, r.Path(rel) != rel)
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, f); err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		if !bytes.Equal(buf.Bytes(), src) {
+			rewritten[rel] = buf.Bytes()
+		}
+	}
+	return rewritten, nil
+}
+
+// parse parses the Go files of the module, leaving out hidden, vendor and
+// testdata directories, and returns their sources by path.
+func (p *project) parse() (map[string][]byte, error) {
+	sources := make(map[string][]byte)
+	err := filepath.WalkDir(p.dir, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if fullPath != p.dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(fullPath) != ".go" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(p.dir, fullPath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		src, err := os.ReadFile(fullPath)
+		if err != nil {
+			return err
+		}
+		f, err := parser.ParseFile(p.fset, rel, src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		sources[rel] = src
+		p.files[path.Dir(rel)] = append(p.files[path.Dir(rel)], f)
+		return nil
+	})
+	return sources, err
+}
+
+func (p *project) file(rel string) *ast.File {
+	for _, f := range p.files[path.Dir(rel)] {
+		if p.fset.File(f.Pos()).Name() == rel {
+			return f
+		}
+	}
+	return nil
+}
+
+// check type-checks every package of the module with its tests, recording
+// the objects of the identifiers. Type errors, such as the ones of
+// dependencies missing from the module cache, leave the identifiers
+// involved unresolved but do not stop the check.
+func (p *project) check() {
+	dirs := make([]string, 0, len(p.files))
+	for dir := range p.files {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		var pkgFiles, testFiles []*ast.File
+		for _, f := range p.files[dir] {
+			if strings.HasSuffix(f.Name.Name, "_test") && strings.HasSuffix(p.fset.File(f.Pos()).Name(), "_test.go") {
+				testFiles = append(testFiles, f)
+			} else {
+				pkgFiles = append(pkgFiles, f)
+			}
+		}
+		importPath := p.importPath(dir)
+		config := types.Config{Importer: p, Error: func(error) {}}
+		for _, files := range [][]*ast.File{pkgFiles, testFiles} {
+			if len(files) > 0 {
+				_, _ = config.Check(importPath, p.fset, files, p.info)
+			}
+		}
+	}
+}
+
+func (p *project) importPath(dir string) string {
+	if dir == "." {
+		return p.module
+	}
+	return path.Join(p.module, dir)
+}
+
+func (p *project) Import(path string) (*types.Package, error) {
+	return p.ImportFrom(path, p.dir, 0)
+}
+
+// ImportFrom type-checks the packages of the module from the parsed files,
+// delegating the other ones to the standard library export data or to their
+// sources.
+func (p *project) ImportFrom(importPath string, dir string, mode types.ImportMode) (*types.Package, error) {
+	if pkg, ok := p.packages[importPath]; ok {
+		return pkg, nil
+	}
+	rel, ok := p.relative(importPath)
+	if !ok {
+		pkg, err := p.stdlib.Import(importPath)
+		if err != nil {
+			pkg, err = p.source.ImportFrom(importPath, p.dir, mode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.packages[importPath] = pkg
+		return pkg, nil
+	}
+
+	if p.checking[importPath] {
+		return nil, fmt.Errorf("import cycle through %s", importPath)
+	}
+	p.checking[importPath] = true
+	defer delete(p.checking, importPath)
+
+	files := make([]*ast.File, 0)
+	for _, f := range p.files[rel] {
+		if !strings.HasSuffix(p.fset.File(f.Pos()).Name(), "_test.go") {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files for %s", importPath)
+	}
+	config := types.Config{Importer: p, Error: func(error) {}}
+	pkg, _ := config.Check(importPath, p.fset, files, nil)
+	p.packages[importPath] = pkg
+	return pkg, nil
+}
+
+// relative returns the directory of the module package at importPath,
+// relative to the module root.
+func (p *project) relative(importPath string) (string, bool) {
+	if importPath == p.module {
+		return ".", true
+	}
+	if !strings.HasPrefix(importPath, p.module+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(importPath, p.module+"/"), true
+}
+
+// declared reports whether obj is declared in a file of the entity.
+func (r Renamer) declared(p *project, obj types.Object) bool {
+	if obj.Pkg() == nil || !obj.Pos().IsValid() {
+		return false
+	}
+	if _, ok := p.relative(obj.Pkg().Path()); !ok {
+		return false
+	}
+	rel := p.fset.File(obj.Pos()).Name()
+	return r.Path(rel) != rel
+}
+
+// rewrite renames the entity in f. The package clause, literals, comments
+// and unresolved identifiers are only renamed in the files of the entity.
+func (r Renamer) rewrite(p *project, f *ast.File, own bool) {
+	pkgNames := r.imports(p, f, own)
+	aliases := make(map[token.Pos]string)
+	if own {
+		f.Name.Name = r.Ident(f.Name.Name)
+		aliases = r.aliases(f)
+	}
+
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.Ident:
+			obj := p.info.Defs[node]
+			if obj == nil {
+				obj = p.info.Uses[node]
+			}
+			if obj == nil {
+				// left unresolved by type errors, such as the ones of a
+				// protobuf package not generated yet: the identifiers of
+				// the files of the entity are renamed by name
+				if own {
+					node.Name = r.Ident(node.Name)
+				}
+				return true
+			}
+			if pkgName, ok := obj.(*types.PkgName); ok {
+				if name, ok := pkgNames[pkgName]; ok {
+					node.Name = name
+				}
+				return true
+			}
+			if !r.declared(p, obj) {
+				return true
+			}
+			if alias, ok := aliases[obj.Pos()]; ok {
+				node.Name = alias
+			} else {
+				node.Name = r.Ident(node.Name)
+			}
+		case *ast.BasicLit:
+			if own && node.Kind == token.STRING {
+				node.Value = r.literal(node.Value)
+			}
+		}
+		return true
+	})
+
+	if own {
+		for _, group := range f.Comments {
+			for _, c := range group.List {
+				c.Text = r.comment(c.Text)
+			}
+		}
+	}
+}
+
+// imports renames the import paths of the packages of the entity and
+// returns the new names of their imports. Aliases are kept, except in the
+// files of the entity, where the aliases named after it are renamed. There,
+// the repository package is aliased when its name is taken by the entity
+// variables, as the service generator does for single word entities.
+func (r Renamer) imports(p *project, f *ast.File, own bool) map[*types.PkgName]string {
+	pkgNames := make(map[*types.PkgName]string)
+	for _, spec := range f.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		rel, ok := p.relative(importPath)
+		if !ok || r.Path(rel) == rel {
+			continue
+		}
+		renamed := p.importPath(r.Path(rel))
+		spec.Path.Value = strconv.Quote(renamed)
+
+		var obj types.Object
+		if spec.Name != nil {
+			obj = p.info.Defs[spec.Name]
+		} else {
+			obj = p.info.Implicits[spec]
+		}
+		pkgName, ok := obj.(*types.PkgName)
+		if !ok || (spec.Name != nil && !own) {
+			continue
+		}
+
+		name := r.Ident(pkgName.Name())
+		if own && name == r.to.CamelCase() && strings.Contains(renamed, "/"+entity.REPOSITORIES_PATH+"/") {
+			name += "repository"
+		}
+		if spec.Name != nil || name != r.Ident(pkgName.Imported().Name()) {
+			spec.Name = &ast.Ident{NamePos: spec.Path.Pos(), Name: name}
+		}
+		pkgNames[pkgName] = name
+	}
+	return pkgNames
+}
+
+// aliases returns the new names of the variables named after the alias of a
+// renamed type, by their position: receivers and the variables assigned a
+// composite literal of the type, as generated constructors do.
+func (r Renamer) aliases(f *ast.File) map[token.Pos]string {
+	aliases := make(map[token.Pos]string)
+	add := func(name *ast.Ident, typeName string) {
+		renamed := r.Ident(typeName)
+		if renamed == typeName || name.Name != entity.NewEntityName(typeName, "", "").Alias() {
+			return
+		}
+		if alias := entity.NewEntityName(renamed, "", "").Alias(); token.IsIdentifier(alias) {
+			aliases[name.Pos()] = alias
+		}
+	}
+
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			if node.Recv != nil && len(node.Recv.List) > 0 && len(node.Recv.List[0].Names) > 0 {
+				add(node.Recv.List[0].Names[0], typeName(node.Recv.List[0].Type))
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE || len(node.Lhs) != 1 || len(node.Rhs) != 1 {
+				return true
+			}
+			value := node.Rhs[0]
+			if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				value = unary.X
+			}
+			name, ok := node.Lhs[0].(*ast.Ident)
+			if lit, isLit := value.(*ast.CompositeLit); ok && isLit {
+				add(name, typeName(lit.Type))
+			}
+		}
+		return true
+	})
+	return aliases
+}
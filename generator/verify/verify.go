@@ -0,0 +1,242 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrTypeCheck = errors.New("generated code does not type-check")
+
+// Verifier type-checks the packages of a module with files overlaid in
+// memory, so that generated code is checked before it is written.
+type Verifier struct {
+	dir     string
+	module  string
+	overlay map[string][]byte
+	stubs   map[string]string
+
+	fset     *token.FileSet
+	packages map[string]*types.Package
+	checking map[string]bool
+	errors   []error
+	stdlib   types.Importer
+	source   types.ImporterFrom
+}
+
+func New(dir string, module string) (*Verifier, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	return &Verifier{
+		dir:      absDir,
+		module:   module,
+		overlay:  make(map[string][]byte),
+		stubs:    make(map[string]string),
+		fset:     fset,
+		packages: make(map[string]*types.Package),
+		checking: make(map[string]bool),
+		stdlib:   importer.Default(),
+		source:   importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+	}, nil
+}
+
+// Add overlays the file at path, relative to the module root.
+func (v *Verifier) Add(path string, src []byte) {
+	v.overlay[filepath.Join(v.dir, path)] = src
+}
+
+// Stub resolves the package at importPath, outside the module, from the Go
+// files of dir instead of the module cache.
+func (v *Verifier) Stub(importPath string, dir string) {
+	v.stubs[importPath] = dir
+}
+
+// Files returns the overlaid Go files, relative to the module root.
+func (v *Verifier) Files() []string {
+	files := make([]string, 0, len(v.overlay))
+	for fullPath := range v.overlay {
+		if strings.HasSuffix(fullPath, ".go") {
+			rel, _ := filepath.Rel(v.dir, fullPath)
+			files = append(files, rel)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Check type-checks the packages holding the overlaid Go files, along with
+// their tests, and reports every type error found in them.
+func (v *Verifier) Check() error {
+	dirs := make(map[string]bool)
+	for _, file := range v.Files() {
+		dirs[filepath.Dir(file)] = true
+	}
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+
+	v.errors = nil
+	for _, dir := range sorted {
+		importPath := path.Join(v.module, filepath.ToSlash(dir))
+		if _, err := v.check(importPath, true); err != nil && len(v.errors) == 0 {
+			v.errors = append(v.errors, err)
+		}
+	}
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(v.errors))
+	for _, err := range v.errors {
+		messages = append(messages, v.relative(err.Error()))
+	}
+	return fmt.Errorf("%w:\n%s", ErrTypeCheck, strings.Join(messages, "\n"))
+}
+
+func (v *Verifier) Import(path string) (*types.Package, error) {
+	return v.ImportFrom(path, v.dir, 0)
+}
+
+// ImportFrom type-checks the packages of the module from the overlay and the
+// disk and the stubbed ones from their directory, delegating the other ones
+// to the standard library export data or to their sources.
+func (v *Verifier) ImportFrom(importPath string, dir string, mode types.ImportMode) (*types.Package, error) {
+	if _, ok := v.packageDir(importPath); ok {
+		return v.check(importPath, false)
+	}
+	if pkg, ok := v.packages[importPath]; ok {
+		return pkg, nil
+	}
+	pkg, err := v.stdlib.Import(importPath)
+	if err != nil {
+		pkg, err = v.source.ImportFrom(importPath, v.dir, mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.packages[importPath] = pkg
+	return pkg, nil
+}
+
+// check type-checks the package of the module, with its in-package tests
+// when tests is set. The errors of the packages with tests, which are the
+// ones holding overlaid files, are collected.
+func (v *Verifier) check(importPath string, tests bool) (*types.Package, error) {
+	if pkg, ok := v.packages[importPath]; ok && !tests {
+		return pkg, nil
+	}
+	if v.checking[importPath] {
+		return nil, fmt.Errorf("import cycle through %s", importPath)
+	}
+	v.checking[importPath] = true
+	defer delete(v.checking, importPath)
+
+	dir, _ := v.packageDir(importPath)
+	files, err := v.parseDir(dir, tests)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files for %s in %s", importPath, dir)
+	}
+
+	config := types.Config{Importer: v}
+	if tests {
+		config.Error = func(err error) { v.errors = append(v.errors, err) }
+	}
+	pkg, err := config.Check(importPath, v.fset, files, nil)
+	if tests {
+		return pkg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.packages[importPath] = pkg
+	return pkg, nil
+}
+
+// packageDir returns the directory of the packages type-checked from their
+// sources: the ones of the module and the stubbed ones.
+func (v *Verifier) packageDir(importPath string) (string, bool) {
+	if dir, ok := v.stubs[importPath]; ok {
+		return dir, true
+	}
+	if importPath != v.module && !strings.HasPrefix(importPath, v.module+"/") {
+		return "", false
+	}
+	return filepath.Join(v.dir, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, v.module), "/"))), true
+}
+
+// parseDir parses the Go files of dir matching the build context, the
+// overlaid ones replacing or adding to the ones on disk. External test
+// packages are left out.
+func (v *Verifier) parseDir(dir string, tests bool) ([]*ast.File, error) {
+	sources := make(map[string][]byte)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			sources[filepath.Join(dir, entry.Name())] = nil
+		}
+	}
+	for fullPath, src := range v.overlay {
+		if filepath.Dir(fullPath) == dir {
+			sources[fullPath] = src
+		}
+	}
+
+	names := make([]string, 0, len(sources))
+	for fullPath := range sources {
+		names = append(names, fullPath)
+	}
+	sort.Strings(names)
+
+	files := make([]*ast.File, 0, len(names))
+	for _, fullPath := range names {
+		name := filepath.Base(fullPath)
+		if !strings.HasSuffix(name, ".go") || (!tests && strings.HasSuffix(name, "_test.go")) {
+			continue
+		}
+		if match, err := build.Default.MatchFile(dir, name); err == nil && !match && sources[fullPath] == nil {
+			continue
+		}
+		src := sources[fullPath]
+		if src == nil {
+			if src, err = os.ReadFile(fullPath); err != nil {
+				return nil, err
+			}
+		}
+		f, err := parser.ParseFile(v.fset, fullPath, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(f.Name.Name, "_test") && strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// relative shortens the absolute file paths of the message to paths
+// relative to the module root.
+func (v *Verifier) relative(message string) string {
+	return strings.ReplaceAll(message, v.dir+string(filepath.Separator), "")
+}
@@ -0,0 +1,122 @@
+package verify_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/verify"
+	"github.com/eduardoths/micro-cli/tests/utils"
+)
+
+const module = "github.com/e/svc"
+
+// newModule writes a module holding an entity struct and returns a verifier
+// of it.
+func newModule(t *testing.T) *verify.Verifier {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module " + module + "\n\ngo 1.19\n",
+		"src/structs/xpto_struct.go": "package structs\n\n" +
+			"type XptoStruct struct {\n" +
+			"\tName string\n" +
+			"}\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := verify.New(dir, module)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return v
+}
+
+func TestVerifier_Check(t *testing.T) {
+	type testCase struct {
+		it      string
+		src     string
+		wantErr []string
+	}
+
+	tc := []testCase{
+		{
+			it: "should accept code using the module packages",
+			src: "package xptostruct\n\n" +
+				"import (\n" +
+				"\t\"context\"\n\n" +
+				"\t\"github.com/e/svc/src/structs\"\n" +
+				")\n\n" +
+				"func Get(ctx context.Context) (structs.XptoStruct, error) {\n" +
+				"\treturn structs.XptoStruct{Name: \"xpto\"}, ctx.Err()\n" +
+				"}\n",
+		},
+		{
+			it: "should report unresolved imports",
+			src: "package xptostruct\n\n" +
+				"import \"github.com/e/svc/src/entities\"\n\n" +
+				"func Get() entities.XptoStruct {\n" +
+				"\treturn entities.XptoStruct{}\n" +
+				"}\n",
+			wantErr: []string{"src/repositories/xpto_struct/xpto_struct_repository.go:3:8", "could not import github.com/e/svc/src/entities"},
+		},
+		{
+			it: "should report missing returns",
+			src: "package xptostruct\n\n" +
+				"func Get() error {\n" +
+				"}\n",
+			wantErr: []string{"src/repositories/xpto_struct/xpto_struct_repository.go:4:1: missing return"},
+		},
+		{
+			it: "should report unknown fields",
+			src: "package xptostruct\n\n" +
+				"import \"github.com/e/svc/src/structs\"\n\n" +
+				"var xpto = structs.XptoStruct{ID: 1}\n",
+			wantErr: []string{"unknown field ID"},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			v := newModule(t)
+			v.Add("src/repositories/xpto_struct/xpto_struct_repository.go", []byte(c.src))
+			err := v.Check()
+			if len(c.wantErr) == 0 {
+				if err != nil {
+					utils.Error(t, nil, err)
+				}
+				return
+			}
+			if !errors.Is(err, verify.ErrTypeCheck) {
+				utils.Error(t, verify.ErrTypeCheck, err)
+				return
+			}
+			for _, want := range c.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					utils.Error(t, want, err.Error())
+				}
+			}
+		})
+	}
+
+	t.Run("it should check the overlaid files against each other", func(t *testing.T) {
+		v := newModule(t)
+		v.Add("src/structs/xpto_struct.go", []byte("package structs\n\ntype XptoStruct struct {\n\tID int\n}\n"))
+		v.Add("src/services/xpto_struct/xpto_struct_service.go", []byte("package xptostruct\n\n"+
+			"import \"github.com/e/svc/src/structs\"\n\n"+
+			"var xpto = structs.XptoStruct{ID: 1}\n"))
+		if err := v.Check(); err != nil {
+			utils.Error(t, nil, err)
+		}
+	})
+}
@@ -2,9 +2,10 @@ module github.com/eduardoths/micro-cli
 
 go 1.19
 
-require github.com/spf13/cobra v1.6.1
-
 require (
-	github.com/inconshreveable/mousetrap v1.0.1 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/cobra v1.6.1
+	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require github.com/inconshreveable/mousetrap v1.0.1 // indirect
@@ -0,0 +1,182 @@
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/generator/verify"
+)
+
+const (
+	DIR       = "testdata"
+	EXTENSION = ".golden"
+
+	// CONTEXT is the number of unchanged lines shown around the changes of
+	// a diff.
+	CONTEXT = 3
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the generated content")
+
+// Path returns the path of the golden file name, relative to the package
+// under test.
+func Path(name string) string {
+	return filepath.Join(DIR, name+EXTENSION)
+}
+
+// Assert compares got with the golden file name, or rewrites the golden file
+// with it when the tests run with -update.
+func Assert(t *testing.T, name string, got string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(DIR, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(Path(name), []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want := Read(t, name)
+	if want != got {
+		t.Errorf("Test %s failed: %s differs, run go test -update to rewrite it\n%s", t.Name(), Path(name), Diff(want, got))
+	}
+}
+
+// Read returns the content of the golden file name.
+func Read(t *testing.T, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(Path(name))
+	if err != nil {
+		t.Fatalf("could not read the golden file, run go test -update to write it: %v", err)
+	}
+	return string(content)
+}
+
+// Compile type-checks the golden files as the files of module, by their path
+// in it. The packages it imports out of the standard library are stubbed by
+// the ones of testdata/stubs, next to this file.
+func Compile(t *testing.T, module string, goldens map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+module+"\n\ngo 1.19\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := verify.New(dir, module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for importPath, stubDir := range stubs(t) {
+		v.Stub(importPath, stubDir)
+	}
+	for path, name := range goldens {
+		v.Add(path, []byte(Read(t, name)))
+	}
+	if err := v.Check(); err != nil {
+		t.Errorf("Test %s failed: %v", t.Name(), err)
+	}
+}
+
+// stubs returns the directories of the stubbed packages by import path.
+func stubs(t *testing.T) map[string]string {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), DIR, "stubs")
+
+	dirs := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".go" {
+			return err
+		}
+		importPath, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		dirs[filepath.ToSlash(importPath)] = filepath.Dir(path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dirs
+}
+
+// Diff returns the lines removed from want and added in got, prefixed with
+// - and + and surrounded by a few unchanged lines.
+func Diff(want string, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	lines := make([]line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// keep the changed lines and their context
+	shown := make(map[int]bool)
+	for n, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for k := n - CONTEXT; k <= n+CONTEXT; k++ {
+			if k >= 0 && k < len(lines) {
+				shown[k] = true
+			}
+		}
+	}
+	indexes := make([]int, 0, len(shown))
+	for n := range shown {
+		indexes = append(indexes, n)
+	}
+	sort.Ints(indexes)
+
+	var sb strings.Builder
+	for k, n := range indexes {
+		if k > 0 && n != indexes[k-1]+1 {
+			sb.WriteString("...\n")
+		}
+		fmt.Fprintf(&sb, "%c %s\n", lines[n].op, lines[n].text)
+	}
+	return sb.String()
+}
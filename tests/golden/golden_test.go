@@ -0,0 +1,40 @@
+package golden_test
+
+import (
+	"testing"
+
+	"github.com/eduardoths/micro-cli/tests/golden"
+)
+
+func TestDiff(t *testing.T) {
+	type testCase struct {
+		it   string
+		want string
+		got  string
+		diff string
+	}
+
+	tc := []testCase{
+		{it: "should be empty for equal contents", want: "a\nb\n", got: "a\nb\n", diff: ""},
+		{
+			it:   "should show the changed lines",
+			want: "a\nb\nc\n",
+			got:  "a\nB\nc\n",
+			diff: "  a\n- b\n+ B\n  c\n  \n",
+		},
+		{
+			it:   "should elide the unchanged lines far from the changes",
+			want: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			got:  "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			diff: "+ 0\n  1\n  2\n  3\n...\n  7\n  8\n  9\n- 10\n  \n",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			if got := golden.Diff(c.want, c.got); got != c.diff {
+				t.Errorf("Diff() failed, want: \n%s\ngot\n%s", c.diff, got)
+			}
+		})
+	}
+}
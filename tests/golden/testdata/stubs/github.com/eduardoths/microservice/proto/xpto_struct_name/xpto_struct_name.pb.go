@@ -0,0 +1,167 @@
+// Package xptostructnamepb stubs the code protoc generates from the proto
+// golden file of generator/entity, imported by the gRPC adapter golden file.
+package xptostructnamepb
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type XptoStructName struct {
+	Id         string
+	Name       string
+	Nickname   *string
+	Tags       []string
+	Count      int64
+	CreatedAt  *timestamppb.Timestamp
+	CustomerId string
+}
+
+func (x *XptoStructName) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *XptoStructName) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *XptoStructName) GetNickname() string {
+	if x != nil && x.Nickname != nil {
+		return *x.Nickname
+	}
+	return ""
+}
+
+func (x *XptoStructName) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *XptoStructName) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *XptoStructName) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *XptoStructName) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+type ListXptoStructNamesRequest struct{}
+
+type ListXptoStructNamesResponse struct {
+	XptoStructNames []*XptoStructName
+}
+
+func (x *ListXptoStructNamesResponse) GetXptoStructNames() []*XptoStructName {
+	if x != nil {
+		return x.XptoStructNames
+	}
+	return nil
+}
+
+type GetXptoStructNameRequest struct {
+	Id string
+}
+
+func (x *GetXptoStructNameRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateXptoStructNameRequest struct {
+	XptoStructName *XptoStructName
+}
+
+func (x *CreateXptoStructNameRequest) GetXptoStructName() *XptoStructName {
+	if x != nil {
+		return x.XptoStructName
+	}
+	return nil
+}
+
+type UpdateXptoStructNameRequest struct {
+	Id             string
+	XptoStructName *XptoStructName
+}
+
+func (x *UpdateXptoStructNameRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateXptoStructNameRequest) GetXptoStructName() *XptoStructName {
+	if x != nil {
+		return x.XptoStructName
+	}
+	return nil
+}
+
+type DeleteXptoStructNameRequest struct {
+	Id string
+}
+
+func (x *DeleteXptoStructNameRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type XptoStructNameServiceServer interface {
+	ListXptoStructNames(context.Context, *ListXptoStructNamesRequest) (*ListXptoStructNamesResponse, error)
+	GetXptoStructName(context.Context, *GetXptoStructNameRequest) (*XptoStructName, error)
+	CreateXptoStructName(context.Context, *CreateXptoStructNameRequest) (*XptoStructName, error)
+	UpdateXptoStructName(context.Context, *UpdateXptoStructNameRequest) (*XptoStructName, error)
+	DeleteXptoStructName(context.Context, *DeleteXptoStructNameRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedXptoStructNameServiceServer()
+}
+
+type UnimplementedXptoStructNameServiceServer struct{}
+
+func (UnimplementedXptoStructNameServiceServer) ListXptoStructNames(context.Context, *ListXptoStructNamesRequest) (*ListXptoStructNamesResponse, error) {
+	return nil, nil
+}
+
+func (UnimplementedXptoStructNameServiceServer) GetXptoStructName(context.Context, *GetXptoStructNameRequest) (*XptoStructName, error) {
+	return nil, nil
+}
+
+func (UnimplementedXptoStructNameServiceServer) CreateXptoStructName(context.Context, *CreateXptoStructNameRequest) (*XptoStructName, error) {
+	return nil, nil
+}
+
+func (UnimplementedXptoStructNameServiceServer) UpdateXptoStructName(context.Context, *UpdateXptoStructNameRequest) (*XptoStructName, error) {
+	return nil, nil
+}
+
+func (UnimplementedXptoStructNameServiceServer) DeleteXptoStructName(context.Context, *DeleteXptoStructNameRequest) (*emptypb.Empty, error) {
+	return nil, nil
+}
+
+func (UnimplementedXptoStructNameServiceServer) mustEmbedUnimplementedXptoStructNameServiceServer() {}
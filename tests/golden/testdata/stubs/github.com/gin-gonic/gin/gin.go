@@ -0,0 +1,46 @@
+// Package gin stubs the API of github.com/gin-gonic/gin used by generated code.
+package gin
+
+import "net/http"
+
+const TestMode = "test"
+
+type HandlerFunc func(*Context)
+
+type IRoutes interface{}
+
+type IRouter interface {
+	IRoutes
+	GET(relativePath string, handlers ...HandlerFunc) IRoutes
+	POST(relativePath string, handlers ...HandlerFunc) IRoutes
+	PUT(relativePath string, handlers ...HandlerFunc) IRoutes
+	DELETE(relativePath string, handlers ...HandlerFunc) IRoutes
+}
+
+type Context struct {
+	Request *http.Request
+}
+
+func (c *Context) JSON(code int, obj any) {}
+
+func (c *Context) Param(key string) string { return "" }
+
+func (c *Context) ShouldBindJSON(obj any) error { return nil }
+
+func (c *Context) Status(code int) {}
+
+type Engine struct{}
+
+func New() *Engine { return &Engine{} }
+
+func SetMode(value string) {}
+
+func (engine *Engine) ServeHTTP(w http.ResponseWriter, req *http.Request) {}
+
+func (engine *Engine) GET(relativePath string, handlers ...HandlerFunc) IRoutes { return engine }
+
+func (engine *Engine) POST(relativePath string, handlers ...HandlerFunc) IRoutes { return engine }
+
+func (engine *Engine) PUT(relativePath string, handlers ...HandlerFunc) IRoutes { return engine }
+
+func (engine *Engine) DELETE(relativePath string, handlers ...HandlerFunc) IRoutes { return engine }
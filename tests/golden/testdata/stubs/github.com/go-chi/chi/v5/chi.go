@@ -0,0 +1,28 @@
+// Package chi stubs the API of github.com/go-chi/chi/v5 used by generated code.
+package chi
+
+import "net/http"
+
+type Router interface {
+	http.Handler
+	Get(pattern string, h http.HandlerFunc)
+	Post(pattern string, h http.HandlerFunc)
+	Put(pattern string, h http.HandlerFunc)
+	Delete(pattern string, h http.HandlerFunc)
+}
+
+type Mux struct{}
+
+func NewRouter() *Mux { return &Mux{} }
+
+func (mx *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func (mx *Mux) Get(pattern string, h http.HandlerFunc) {}
+
+func (mx *Mux) Post(pattern string, h http.HandlerFunc) {}
+
+func (mx *Mux) Put(pattern string, h http.HandlerFunc) {}
+
+func (mx *Mux) Delete(pattern string, h http.HandlerFunc) {}
+
+func URLParam(r *http.Request, key string) string { return "" }
@@ -0,0 +1,46 @@
+// Package fiber stubs the API of github.com/gofiber/fiber/v2 used by generated code.
+package fiber
+
+import (
+	"context"
+	"net/http"
+)
+
+type Handler func(*Ctx) error
+
+type Router interface {
+	Get(path string, handlers ...Handler) Router
+	Post(path string, handlers ...Handler) Router
+	Put(path string, handlers ...Handler) Router
+	Delete(path string, handlers ...Handler) Router
+}
+
+type Ctx struct{}
+
+func (c *Ctx) BodyParser(out any) error { return nil }
+
+func (c *Ctx) JSON(data any, ctype ...string) error { return nil }
+
+func (c *Ctx) Params(key string, defaultValue ...string) string { return "" }
+
+func (c *Ctx) SendStatus(status int) error { return nil }
+
+func (c *Ctx) Status(status int) *Ctx { return c }
+
+func (c *Ctx) UserContext() context.Context { return context.Background() }
+
+type Config struct{}
+
+type App struct{}
+
+func New(config ...Config) *App { return &App{} }
+
+func (app *App) Test(req *http.Request, msTimeout ...int) (*http.Response, error) { return nil, nil }
+
+func (app *App) Get(path string, handlers ...Handler) Router { return app }
+
+func (app *App) Post(path string, handlers ...Handler) Router { return app }
+
+func (app *App) Put(path string, handlers ...Handler) Router { return app }
+
+func (app *App) Delete(path string, handlers ...Handler) Router { return app }
@@ -0,0 +1,12 @@
+// Package uuid stubs the API of github.com/google/uuid used by generated code.
+package uuid
+
+type UUID [16]byte
+
+func New() UUID { return UUID{} }
+
+func NewString() string { return New().String() }
+
+func Parse(s string) (UUID, error) { return UUID{}, nil }
+
+func (u UUID) String() string { return "" }
@@ -0,0 +1,36 @@
+// Package echo stubs the API of github.com/labstack/echo/v4 used by generated code.
+package echo
+
+import "net/http"
+
+type HandlerFunc func(c Context) error
+
+type MiddlewareFunc func(next HandlerFunc) HandlerFunc
+
+type Context interface {
+	Request() *http.Request
+	Param(name string) string
+	Bind(i any) error
+	JSON(code int, i any) error
+	NoContent(code int) error
+}
+
+type Route struct{}
+
+type Group struct{}
+
+func (g *Group) GET(path string, h HandlerFunc, m ...MiddlewareFunc) *Route { return &Route{} }
+
+func (g *Group) POST(path string, h HandlerFunc, m ...MiddlewareFunc) *Route { return &Route{} }
+
+func (g *Group) PUT(path string, h HandlerFunc, m ...MiddlewareFunc) *Route { return &Route{} }
+
+func (g *Group) DELETE(path string, h HandlerFunc, m ...MiddlewareFunc) *Route { return &Route{} }
+
+type Echo struct{}
+
+func New() *Echo { return &Echo{} }
+
+func (e *Echo) Group(prefix string, m ...MiddlewareFunc) *Group { return &Group{} }
+
+func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
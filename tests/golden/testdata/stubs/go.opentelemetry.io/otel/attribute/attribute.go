@@ -0,0 +1,29 @@
+// Package attribute stubs the API of go.opentelemetry.io/otel/attribute used
+// by generated code.
+package attribute
+
+import "fmt"
+
+type KeyValue struct{}
+
+func String(k string, v string) KeyValue { return KeyValue{} }
+
+func Bool(k string, v bool) KeyValue { return KeyValue{} }
+
+func Int(k string, v int) KeyValue { return KeyValue{} }
+
+func Int64(k string, v int64) KeyValue { return KeyValue{} }
+
+func Float64(k string, v float64) KeyValue { return KeyValue{} }
+
+func StringSlice(k string, v []string) KeyValue { return KeyValue{} }
+
+func BoolSlice(k string, v []bool) KeyValue { return KeyValue{} }
+
+func IntSlice(k string, v []int) KeyValue { return KeyValue{} }
+
+func Int64Slice(k string, v []int64) KeyValue { return KeyValue{} }
+
+func Float64Slice(k string, v []float64) KeyValue { return KeyValue{} }
+
+func Stringer(k string, v fmt.Stringer) KeyValue { return KeyValue{} }
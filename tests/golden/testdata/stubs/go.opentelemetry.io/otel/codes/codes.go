@@ -0,0 +1,11 @@
+// Package codes stubs the API of go.opentelemetry.io/otel/codes used by
+// generated code.
+package codes
+
+type Code uint32
+
+const (
+	Unset Code = iota
+	Error
+	Ok
+)
@@ -0,0 +1,24 @@
+// Package trace stubs the API of go.opentelemetry.io/otel/trace used by
+// generated code.
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type Tracer interface {
+	Start(ctx context.Context, spanName string, opts ...SpanStartOption) (context.Context, Span)
+}
+
+type Span interface {
+	End()
+	RecordError(err error)
+	SetStatus(code codes.Code, description string)
+}
+
+type SpanStartOption interface{}
+
+func WithAttributes(attributes ...attribute.KeyValue) SpanStartOption { return nil }
@@ -0,0 +1,5 @@
+// Package emptypb stubs the API of
+// google.golang.org/protobuf/types/known/emptypb used by generated code.
+package emptypb
+
+type Empty struct{}
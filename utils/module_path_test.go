@@ -0,0 +1,59 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eduardoths/micro-cli/utils"
+)
+
+func TestModulePath(t *testing.T) {
+	type testCase struct {
+		it      string
+		gomod   string
+		want    string
+		wantErr bool
+	}
+
+	tc := []testCase{
+		{
+			it:    "should read the module path",
+			gomod: "module github.com/eduardoths/microservice\n\ngo 1.19\n",
+			want:  "github.com/eduardoths/microservice",
+		},
+		{
+			it:    "should read a quoted module path",
+			gomod: "// comment\nmodule \"github.com/eduardoths/microservice\"\n",
+			want:  "github.com/eduardoths/microservice",
+		},
+		{
+			it:    "should skip lines only starting with the module keyword",
+			gomod: "modulex github.com/eduardoths/other\nmodule\tgithub.com/eduardoths/microservice // comment\n",
+			want:  "github.com/eduardoths/microservice",
+		},
+		{
+			it:      "should fail without a module directive",
+			gomod:   "go 1.19\n",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(c.gomod), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := utils.ModulePath(dir)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("TestModulePath failed.\nunexpected error: %v", err)
+			}
+			if c.want != actual {
+				t.Errorf("TestModulePath failed.\nGot:\t\t%s\nwant:\t%s", actual, c.want)
+				t.Logf("Case: %s", c.it)
+			}
+		})
+	}
+}
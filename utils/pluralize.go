@@ -0,0 +1,49 @@
+package utils
+
+import "strings"
+
+func Pluralize(str string) string {
+	lower := strings.ToLower(str)
+	switch {
+	case str == "":
+		return str
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !isVowel(lower[len(lower)-2]):
+		return str[:len(str)-1] + "ies"
+	case strings.HasSuffix(lower, "s"),
+		strings.HasSuffix(lower, "x"),
+		strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"),
+		strings.HasSuffix(lower, "sh"):
+		return str + "es"
+	default:
+		return str + "s"
+	}
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) >= 0
+}
+
+// Singularize reverts the rules of Pluralize, leaving words that do not look
+// plural untouched.
+func Singularize(str string) string {
+	lower := strings.ToLower(str)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		return str[:len(str)-3] + "y"
+	case strings.HasSuffix(lower, "sses"),
+		strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"),
+		strings.HasSuffix(lower, "shes"):
+		return str[:len(str)-2]
+	case strings.HasSuffix(lower, "ss"),
+		strings.HasSuffix(lower, "us"),
+		strings.HasSuffix(lower, "is"):
+		return str
+	case strings.HasSuffix(lower, "s"):
+		return str[:len(str)-1]
+	default:
+		return str
+	}
+}
@@ -0,0 +1,99 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/eduardoths/micro-cli/utils"
+)
+
+func TestPluralize(t *testing.T) {
+	type testCase struct {
+		it   string
+		in   string
+		want string
+	}
+
+	tc := []testCase{
+		{
+			it:   "should append an s",
+			in:   "XptoStruct",
+			want: "XptoStructs",
+		},
+		{
+			it:   "should replace a consonant followed by y with ies",
+			in:   "category",
+			want: "categories",
+		},
+		{
+			it:   "should keep a vowel followed by y",
+			in:   "Key",
+			want: "Keys",
+		},
+		{
+			it:   "should append es to sibilant endings",
+			in:   "address_box",
+			want: "address_boxes",
+		},
+		{
+			it:   "should return empty strings untouched",
+			in:   "",
+			want: "",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			actual := utils.Pluralize(c.in)
+			if c.want != actual {
+				t.Errorf("TestPluralize failed.\nGot:\t\t%s\nwant:\t%s", actual, c.want)
+				t.Logf("Case: %s", c.it)
+			}
+		})
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	type testCase struct {
+		it   string
+		in   string
+		want string
+	}
+
+	tc := []testCase{
+		{
+			it:   "should remove a trailing s",
+			in:   "order_items",
+			want: "order_item",
+		},
+		{
+			it:   "should replace ies with y",
+			in:   "categories",
+			want: "category",
+		},
+		{
+			it:   "should remove es from sibilant endings",
+			in:   "addresses",
+			want: "address",
+		},
+		{
+			it:   "should keep words that are not plural",
+			in:   "status",
+			want: "status",
+		},
+		{
+			it:   "should keep words that do not end with s",
+			in:   "person",
+			want: "person",
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.it, func(t *testing.T) {
+			actual := utils.Singularize(c.in)
+			if c.want != actual {
+				t.Errorf("TestSingularize failed.\nGot:\t\t%s\nwant:\t%s", actual, c.want)
+				t.Logf("Case: %s", c.it)
+			}
+		})
+	}
+}